@@ -0,0 +1,366 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package addrtemplate implements a small go-sockaddr style templating
+// language for ServiceEntry endpoint and Pilot bind addresses. A template
+// such as
+//
+//	{{ GetAllInterfaces | include "network" "10.0.0.0/8" | attr "address" }}
+//
+// lets operators write a single address expression that resolves to a
+// concrete IP on whatever host it ends up running on, rather than
+// templating YAML out-of-band per node.
+package addrtemplate
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// delimiters used to recognize a template string, e.g. "{{ GetPrivateIP }}".
+const (
+	openDelim  = "{{"
+	closeDelim = "}}"
+)
+
+// IsTemplate reports whether addr looks like an addrtemplate expression.
+func IsTemplate(addr string) bool {
+	return strings.HasPrefix(strings.TrimSpace(addr), openDelim)
+}
+
+// stage is a single step of the pipeline, e.g. `include "network" "10.0.0.0/8"`.
+type stage struct {
+	name string
+	args []string
+}
+
+// Template is a parsed, but not yet resolved, address expression.
+type Template struct {
+	raw    string
+	stages []stage
+}
+
+// Parse parses src into a Template, failing on syntax errors (unknown
+// function names, unbalanced quotes, a missing source stage). Parse
+// succeeds even when no interface on the validating host could satisfy the
+// template - that is a Resolve-time concern.
+func Parse(src string) (Template, error) {
+	trimmed := strings.TrimSpace(src)
+	if !strings.HasPrefix(trimmed, openDelim) || !strings.HasSuffix(trimmed, closeDelim) {
+		return Template{}, fmt.Errorf("addrtemplate: %q is not a template (must be wrapped in %s %s)", src, openDelim, closeDelim)
+	}
+	body := strings.TrimSpace(trimmed[len(openDelim) : len(trimmed)-len(closeDelim)])
+	if body == "" {
+		return Template{}, fmt.Errorf("addrtemplate: empty template")
+	}
+
+	parts, err := splitPipeline(body)
+	if err != nil {
+		return Template{}, err
+	}
+
+	stages := make([]stage, 0, len(parts))
+	for i, part := range parts {
+		s, err := parseStage(part)
+		if err != nil {
+			return Template{}, fmt.Errorf("addrtemplate: stage %d: %v", i, err)
+		}
+		if _, ok := knownFuncs[s.name]; !ok {
+			return Template{}, fmt.Errorf("addrtemplate: unknown function %q", s.name)
+		}
+		stages = append(stages, s)
+	}
+
+	if _, ok := sourceFuncs[stages[0].name]; !ok {
+		return Template{}, fmt.Errorf("addrtemplate: %q must begin with a source function (one of %s)", src, strings.Join(sourceNames(), ", "))
+	}
+
+	return Template{raw: src, stages: stages}, nil
+}
+
+// String returns the original template source.
+func (t Template) String() string {
+	return t.raw
+}
+
+// Resolve evaluates the template against the local host's network
+// interfaces and returns the resolved address. It always returns a
+// deterministic error (rather than panicking or blocking) when no
+// interface satisfies the template.
+func (t Template) Resolve(ctx context.Context) (string, error) {
+	if len(t.stages) == 0 {
+		return "", fmt.Errorf("addrtemplate: cannot resolve an empty template")
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	var val value
+	var err error
+	for i, s := range t.stages {
+		fn := knownFuncs[s.name]
+		val, err = fn(val, s.args)
+		if err != nil {
+			return "", fmt.Errorf("addrtemplate: %q: stage %d (%s): %v", t.raw, i, s.name, err)
+		}
+	}
+
+	return val.attr("address")
+}
+
+// value is the intermediate result threaded through a pipeline: either a
+// single interface address or a list of them.
+type value struct {
+	entries []ifaceAddr
+}
+
+type ifaceAddr struct {
+	ifaceName string
+	ip        net.IP
+	network   *net.IPNet
+}
+
+func (v value) attr(name string) (string, error) {
+	if len(v.entries) == 0 {
+		return "", fmt.Errorf("no matching interface address found")
+	}
+	switch name {
+	case "address":
+		return v.entries[0].ip.String(), nil
+	case "name":
+		return v.entries[0].ifaceName, nil
+	default:
+		return "", fmt.Errorf("unknown attribute %q", name)
+	}
+}
+
+// pipelineFunc implements one stage of the pipeline, given the value
+// produced upstream (zero value for the first, source, stage) and the
+// stage's string arguments.
+type pipelineFunc func(in value, args []string) (value, error)
+
+var sourceFuncs = map[string]bool{
+	"GetPrivateIP":     true,
+	"GetPublicIP":      true,
+	"GetInterfaceIP":   true,
+	"GetAllInterfaces": true,
+}
+
+func sourceNames() []string {
+	names := make([]string, 0, len(sourceFuncs))
+	for n := range sourceFuncs {
+		names = append(names, n)
+	}
+	return names
+}
+
+var knownFuncs = map[string]pipelineFunc{
+	"GetPrivateIP":     fnGetPrivateIP,
+	"GetPublicIP":      fnGetPublicIP,
+	"GetInterfaceIP":   fnGetInterfaceIP,
+	"GetAllInterfaces": fnGetAllInterfaces,
+	"include":          fnInclude,
+	"attr":             fnAttr,
+}
+
+func allInterfaceAddrs() ([]ifaceAddr, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var out []ifaceAddr
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			out = append(out, ifaceAddr{ifaceName: iface.Name, ip: ipNet.IP, network: ipNet})
+		}
+	}
+	return out, nil
+}
+
+func fnGetAllInterfaces(_ value, _ []string) (value, error) {
+	addrs, err := allInterfaceAddrs()
+	if err != nil {
+		return value{}, err
+	}
+	return value{entries: addrs}, nil
+}
+
+func fnGetPrivateIP(_ value, _ []string) (value, error) {
+	addrs, err := allInterfaceAddrs()
+	if err != nil {
+		return value{}, err
+	}
+	for _, a := range addrs {
+		if isPrivate(a.ip) {
+			return value{entries: []ifaceAddr{a}}, nil
+		}
+	}
+	return value{}, nil
+}
+
+func fnGetPublicIP(_ value, _ []string) (value, error) {
+	addrs, err := allInterfaceAddrs()
+	if err != nil {
+		return value{}, err
+	}
+	for _, a := range addrs {
+		if !a.ip.IsLoopback() && !isPrivate(a.ip) {
+			return value{entries: []ifaceAddr{a}}, nil
+		}
+	}
+	return value{}, nil
+}
+
+func fnGetInterfaceIP(_ value, args []string) (value, error) {
+	if len(args) != 1 {
+		return value{}, fmt.Errorf("GetInterfaceIP takes exactly one argument (the interface name)")
+	}
+	addrs, err := allInterfaceAddrs()
+	if err != nil {
+		return value{}, err
+	}
+	for _, a := range addrs {
+		if a.ifaceName == args[0] {
+			return value{entries: []ifaceAddr{a}}, nil
+		}
+	}
+	return value{}, nil
+}
+
+func fnInclude(in value, args []string) (value, error) {
+	if len(args) != 2 {
+		return value{}, fmt.Errorf(`include takes exactly two arguments, e.g. include "network" "10.0.0.0/8"`)
+	}
+	switch args[0] {
+	case "network":
+		_, cidr, err := net.ParseCIDR(args[1])
+		if err != nil {
+			return value{}, fmt.Errorf("invalid network %q: %v", args[1], err)
+		}
+		var out []ifaceAddr
+		for _, a := range in.entries {
+			if cidr.Contains(a.ip) {
+				out = append(out, a)
+			}
+		}
+		return value{entries: out}, nil
+	default:
+		return value{}, fmt.Errorf("unknown include selector %q", args[0])
+	}
+}
+
+func fnAttr(in value, args []string) (value, error) {
+	if len(args) != 1 {
+		return value{}, fmt.Errorf(`attr takes exactly one argument, e.g. attr "address"`)
+	}
+	if _, err := in.attr(args[0]); err != nil {
+		return value{}, err
+	}
+	return in, nil
+}
+
+func isPrivate(ip net.IP) bool {
+	for _, cidr := range []string{
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"fc00::/7",
+	} {
+		_, block, err := net.ParseCIDR(cidr)
+		if err == nil && block.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPipeline splits a template body on top-level "|" characters,
+// respecting quoted string arguments so a quoted "|" (unlikely, but legal)
+// doesn't get treated as a separator.
+func splitPipeline(body string) ([]string, error) {
+	var parts []string
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range body {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == '|' && !inQuotes:
+			parts = append(parts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("addrtemplate: unterminated quoted string in %q", body)
+	}
+	parts = append(parts, strings.TrimSpace(cur.String()))
+	return parts, nil
+}
+
+// parseStage parses a single pipeline stage into its function name and
+// string arguments, e.g. `include "network" "10.0.0.0/8"` -> ("include",
+// ["network", "10.0.0.0/8"]).
+func parseStage(s string) (stage, error) {
+	fields, err := tokenize(s)
+	if err != nil {
+		return stage{}, err
+	}
+	if len(fields) == 0 {
+		return stage{}, fmt.Errorf("empty stage")
+	}
+	return stage{name: fields[0], args: fields[1:]}, nil
+}
+
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quoted string in %q", s)
+	}
+	flush()
+	return tokens, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addrtemplate
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestIsTemplate(t *testing.T) {
+	cases := []struct {
+		addr string
+		want bool
+	}{
+		{"{{ GetPrivateIP }}", true},
+		{"  {{ GetPrivateIP }}", true},
+		{"10.0.0.1", false},
+		{"unix:///var/run/istio.sock", false},
+	}
+	for _, c := range cases {
+		if got := IsTemplate(c.addr); got != c.want {
+			t.Errorf("IsTemplate(%q) = %v, want %v", c.addr, got, c.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		wantErr bool
+	}{
+		{"simple source", "{{ GetPrivateIP }}", false},
+		{"pipeline", `{{ GetAllInterfaces | include "network" "10.0.0.0/8" | attr "address" }}`, false},
+		{"interface selector", `{{ GetInterfaceIP "eth0" }}`, false},
+		{"missing delimiters", "GetPrivateIP", true},
+		{"empty body", "{{ }}", true},
+		{"unknown function", "{{ NotAFunction }}", true},
+		{"must start with source", `{{ attr "address" }}`, true},
+		{"unterminated quote", `{{ include "network" "10.0.0.0/8 }}`, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Parse(c.src)
+			if (err != nil) != c.wantErr {
+				t.Errorf("Parse(%q) error = %v, wantErr %v", c.src, err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateStringReturnsSource(t *testing.T) {
+	const src = "{{ GetPrivateIP }}"
+	tmpl, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", src, err)
+	}
+	if got := tmpl.String(); got != src {
+		t.Errorf("String() = %q, want %q", got, src)
+	}
+}
+
+func TestResolveEmptyTemplate(t *testing.T) {
+	var tmpl Template
+	if _, err := tmpl.Resolve(context.Background()); err == nil {
+		t.Error("Resolve() on the zero Template = nil error, want error")
+	}
+}
+
+func TestResolveRespectsCanceledContext(t *testing.T) {
+	tmpl, err := Parse("{{ GetPrivateIP }}")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := tmpl.Resolve(ctx); err == nil {
+		t.Error("Resolve() with an already-canceled context = nil error, want error")
+	}
+}
+
+func TestResolveGetAllInterfacesThenAttr(t *testing.T) {
+	tmpl, err := Parse(`{{ GetAllInterfaces | attr "address" }}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	addr, err := tmpl.Resolve(context.Background())
+	// The sandbox's interfaces are unknown ahead of time, so this only
+	// checks that a resolved address (if any) is a real IP, and that a "no
+	// matching interface" failure is a deterministic error, not a panic.
+	if err != nil {
+		return
+	}
+	if net.ParseIP(addr) == nil {
+		t.Errorf("Resolve() = %q, want a valid IP address", addr)
+	}
+}
+
+func TestResolveGetInterfaceIPUnknownName(t *testing.T) {
+	tmpl, err := Parse(`{{ GetInterfaceIP "definitely-not-a-real-interface" }}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := tmpl.Resolve(context.Background()); err == nil {
+		t.Error("Resolve() for a nonexistent interface = nil error, want error")
+	}
+}
+
+func TestIsPrivate(t *testing.T) {
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"8.8.8.8", false},
+		{"fc00::1", true},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, c := range cases {
+		ip := net.ParseIP(c.ip)
+		if ip == nil {
+			t.Fatalf("net.ParseIP(%q) = nil", c.ip)
+		}
+		if got := isPrivate(ip); got != c.want {
+			t.Errorf("isPrivate(%q) = %v, want %v", c.ip, got, c.want)
+		}
+	}
+}
+
+func TestSplitPipeline(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{"single stage", "GetPrivateIP", []string{"GetPrivateIP"}, false},
+		{
+			"multi-stage",
+			`GetAllInterfaces | include "network" "10.0.0.0/8" | attr "address"`,
+			[]string{"GetAllInterfaces", `include "network" "10.0.0.0/8"`, `attr "address"`},
+			false,
+		},
+		{"quoted pipe is not a separator", `attr "a|b"`, []string{`attr "a|b"`}, false},
+		{"unterminated quote", `attr "a`, nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := splitPipeline(c.body)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("splitPipeline(%q) error = %v, wantErr %v", c.body, err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("splitPipeline(%q) = %v, want %v", c.body, got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("splitPipeline(%q)[%d] = %q, want %q", c.body, i, got[i], c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{"no args", "GetPrivateIP", []string{"GetPrivateIP"}, false},
+		{"two args", `include "network" "10.0.0.0/8"`, []string{"include", "network", "10.0.0.0/8"}, false},
+		{"unterminated quote", `attr "address`, nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := tokenize(c.s)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("tokenize(%q) error = %v, wantErr %v", c.s, err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("tokenize(%q) = %v, want %v", c.s, got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("tokenize(%q)[%d] = %q, want %q", c.s, i, got[i], c.want[i])
+				}
+			}
+		})
+	}
+}
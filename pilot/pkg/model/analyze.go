@@ -0,0 +1,36 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/diag"
+)
+
+// AnalysisInputs is the snapshot of typed configs and observed workload
+// labels that AnalyzeConfig runs istio.io/istio/pkg/config/analysis over.
+// It mirrors what a ConfigStore.List call per-kind would return; Galley and
+// istioctl build one of these from their respective config sources.
+type AnalysisInputs = analysis.Snapshot
+
+// AnalyzeConfig runs the cross-resource analyzers in
+// istio.io/istio/pkg/config/analysis over inputs. Unlike the per-object
+// Validate* functions in this package, these checks require seeing more
+// than one resource at a time (e.g. "does this VirtualService's subset
+// exist in some DestinationRule") and so run as a separate, opt-in pass
+// after per-object validation has already passed.
+func AnalyzeConfig(inputs *AnalysisInputs) diag.Messages {
+	return analysis.Analyze(inputs)
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package field
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorType is a machine-readable classification of why a field failed
+// validation, mirroring k8s.io/apimachinery's field.ErrorType.
+type ErrorType string
+
+const (
+	// ErrorTypeRequired means a required field was empty or unset.
+	ErrorTypeRequired ErrorType = "FieldValueRequired"
+	// ErrorTypeInvalid means a field's value failed validation.
+	ErrorTypeInvalid ErrorType = "FieldValueInvalid"
+	// ErrorTypeNotSupported means a field's value is not one of the
+	// values the API supports.
+	ErrorTypeNotSupported ErrorType = "FieldValueNotSupported"
+	// ErrorTypeForbidden means a field cannot be set given the value of
+	// some other field.
+	ErrorTypeForbidden ErrorType = "FieldValueForbidden"
+	// ErrorTypeDuplicate means a field collides with another field or
+	// element that must be unique.
+	ErrorTypeDuplicate ErrorType = "FieldValueDuplicate"
+)
+
+// Error is a single structured validation failure: which field, what value
+// it had, and why it was rejected.
+type Error struct {
+	Type     ErrorType
+	Field    string
+	BadValue interface{}
+	Detail   string
+}
+
+func (e *Error) Error() string {
+	switch e.Type {
+	case ErrorTypeRequired:
+		return fmt.Sprintf("%s: Required value%s", e.Field, detailSuffix(e.Detail))
+	case ErrorTypeForbidden:
+		return fmt.Sprintf("%s: Forbidden%s", e.Field, detailSuffix(e.Detail))
+	default:
+		return fmt.Sprintf("%s: %s%s%s", e.Field, e.Type, valueSuffix(e.BadValue), detailSuffix(e.Detail))
+	}
+}
+
+func detailSuffix(detail string) string {
+	if detail == "" {
+		return ""
+	}
+	return ": " + detail
+}
+
+func valueSuffix(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf(" %q", fmt.Sprint(value))
+}
+
+// Required returns an Error indicating a required field was unset.
+func Required(fldPath *Path, detail string) *Error {
+	return &Error{Type: ErrorTypeRequired, Field: fldPath.String(), Detail: detail}
+}
+
+// Invalid returns an Error indicating a field's value is invalid.
+func Invalid(fldPath *Path, value interface{}, detail string) *Error {
+	return &Error{Type: ErrorTypeInvalid, Field: fldPath.String(), BadValue: value, Detail: detail}
+}
+
+// NotSupported returns an Error indicating a field's value is not one of
+// validValues.
+func NotSupported(fldPath *Path, value interface{}, validValues []string) *Error {
+	detail := ""
+	if len(validValues) > 0 {
+		detail = "supported values: " + strings.Join(validValues, ", ")
+	}
+	return &Error{Type: ErrorTypeNotSupported, Field: fldPath.String(), BadValue: value, Detail: detail}
+}
+
+// Forbidden returns an Error indicating a field cannot be set in this
+// context.
+func Forbidden(fldPath *Path, detail string) *Error {
+	return &Error{Type: ErrorTypeForbidden, Field: fldPath.String(), Detail: detail}
+}
+
+// Duplicate returns an Error indicating a field's value collides with
+// another element that must be unique.
+func Duplicate(fldPath *Path, value interface{}) *Error {
+	return &Error{Type: ErrorTypeDuplicate, Field: fldPath.String(), BadValue: value}
+}
+
+// ErrorList is a collection of field Errors accumulated while validating a
+// single config object.
+type ErrorList []*Error
+
+// Append adds err to the list, skipping nil errors so callers can append
+// the result of a conditional check unconditionally.
+func (list ErrorList) Append(errs ...*Error) ErrorList {
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	return list
+}
+
+// WrapError appends err, if non-nil, as an Invalid Error at fldPath. It
+// lets existing `error`-returning helpers plug into an ErrorList without
+// being rewritten to build *Error themselves.
+func (list ErrorList) WrapError(fldPath *Path, value interface{}, err error) ErrorList {
+	if err == nil {
+		return list
+	}
+	return list.Append(Invalid(fldPath, value, err.Error()))
+}
+
+// ToAggregate collapses the list into a single error suitable for returning
+// from a model.Validate* function, or nil if the list is empty.
+func (list ErrorList) ToAggregate() error {
+	if len(list) == 0 {
+		return nil
+	}
+	messages := make([]string, len(list))
+	for i, err := range list {
+		messages[i] = err.Error()
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
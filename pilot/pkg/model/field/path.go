@@ -0,0 +1,86 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package field builds up the dotted/indexed path to the part of a config
+// object a validation error is about (e.g. "spec.endpoints[2].ports.http"),
+// in the spirit of k8s.io/apimachinery/pkg/util/validation/field. It exists
+// so istioctl validate and the admission webhook can point an operator at
+// the exact offending field instead of a flat multierror string.
+package field
+
+import "fmt"
+
+// Path represents the path from a config object's root to one of its
+// fields. Each Path is immutable; Child/Index/Key return a new Path rather
+// than mutating the receiver, so a single root Path can be reused across
+// sibling fields.
+type Path struct {
+	name   string
+	parent *Path
+}
+
+// NewPath creates a root Path from one or more path segments, e.g.
+// NewPath("spec", "endpoints").
+func NewPath(name string, moreNames ...string) *Path {
+	p := &Path{name: name}
+	for _, more := range moreNames {
+		p = &Path{name: more, parent: p}
+	}
+	return p
+}
+
+// Child returns a new Path for a named field nested under p, e.g.
+// NewPath("spec").Child("host").
+func (p *Path) Child(name string, moreNames ...string) *Path {
+	return NewPath(name, moreNames...).prependParent(p)
+}
+
+// Index returns a new Path for the i'th element of a repeated field nested
+// under p, e.g. NewPath("spec", "endpoints").Index(2).
+func (p *Path) Index(i int) *Path {
+	return &Path{name: fmt.Sprintf("[%d]", i), parent: p}
+}
+
+// Key returns a new Path for the value keyed by key in a map field nested
+// under p, e.g. NewPath("spec", "endpoints").Index(2).Child("ports").Key("http").
+func (p *Path) Key(key string) *Path {
+	return &Path{name: "." + key, parent: p}
+}
+
+func (p *Path) prependParent(root *Path) *Path {
+	if p.parent == nil {
+		return &Path{name: p.name, parent: root}
+	}
+	return &Path{name: p.name, parent: p.parent.prependParent(root)}
+}
+
+// String renders the full dotted/indexed path, e.g.
+// "spec.endpoints[2].ports.http".
+func (p *Path) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	var segments []string
+	for cur := p; cur != nil; cur = cur.parent {
+		segments = append([]string{cur.name}, segments...)
+	}
+	out := ""
+	for i, s := range segments {
+		if i > 0 && s[0] != '[' && s[0] != '.' {
+			out += "."
+		}
+		out += s
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchexpr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokInt
+	tokCIDR
+	tokEq
+	tokNeq
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokDot
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lex tokenizes a matchexpr source string. It does not itself validate
+// semantics (unknown identifiers, type errors) - that is the parser's job -
+// but it does reject malformed literals (unterminated strings).
+func lex(src string) ([]token, error) {
+	var toks []token
+	runes := []rune(src)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "("})
+			i++
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")"})
+			i++
+		case r == '[':
+			toks = append(toks, token{kind: tokLBracket, text: "["})
+			i++
+		case r == ']':
+			toks = append(toks, token{kind: tokRBracket, text: "]"})
+			i++
+		case r == ',':
+			toks = append(toks, token{kind: tokComma, text: ","})
+			i++
+		case r == '.':
+			toks = append(toks, token{kind: tokDot, text: "."})
+			i++
+		case r == '=' && i+1 < len(runes) && runes[i+1] == '=':
+			toks = append(toks, token{kind: tokEq, text: "=="})
+			i += 2
+		case r == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			toks = append(toks, token{kind: tokNeq, text: "!="})
+			i += 2
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("matchexpr: unterminated string literal in %q", src)
+			}
+			toks = append(toks, token{kind: tokString, text: string(runes[i+1 : j])})
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == '/') {
+				j++
+			}
+			text := string(runes[i:j])
+			if strings.Contains(text, "/") {
+				toks = append(toks, token{kind: tokCIDR, text: text})
+			} else {
+				toks = append(toks, token{kind: tokInt, text: text})
+			}
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: string(runes[i:j])})
+			i = j
+		default:
+			// Unrecognized character: emit it as its own identifier token so
+			// the parser can produce a clear "unexpected token" error rather
+			// than lex silently dropping input.
+			toks = append(toks, token{kind: tokIdent, text: string(r)})
+			i++
+		}
+	}
+	return toks, nil
+}
@@ -0,0 +1,508 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package matchexpr implements the small boolean expression language used
+// by RouteRule.Match.Expression, in the spirit of hashicorp/go-bexpr:
+//
+//	request.headers["x-user-tier"] == "gold" and
+//	  (source.labels["env"] in ["prod", "canary"] or destination.port == 8080)
+//
+// Expressions are parsed once at config-load time (Parse), so a rule with
+// an unknown attribute path or a type mismatch is rejected before it ever
+// reaches a sidecar; compiled expressions are then evaluated per-request
+// (Eval) against an EvalContext.
+package matchexpr
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EvalContext gives typed access to the request/connection attributes an
+// Expr may reference.
+type EvalContext interface {
+	RequestHeader(name string) (string, bool)
+	RequestMethod() string
+	RequestURI() string
+	SourceLabel(name string) (string, bool)
+	SourceNamespace() string
+	DestinationPort() int
+	ConnectionRemoteIP() net.IP
+}
+
+// attrType is the static type of an attribute path, used to reject type
+// mismatches (e.g. comparing destination.port, an int, against a string
+// literal) at parse time.
+type attrType int
+
+const (
+	typeString attrType = iota
+	typeInt
+	typeIP
+)
+
+func (t attrType) String() string {
+	switch t {
+	case typeString:
+		return "string"
+	case typeInt:
+		return "int"
+	case typeIP:
+		return "IP"
+	default:
+		return "unknown"
+	}
+}
+
+// attribute describes one recognized attribute path.
+type attribute struct {
+	root   string
+	typ    attrType
+	mapKey bool // true if the path takes a ["key"] subscript, e.g. request.headers["x"]
+}
+
+var knownAttributes = map[string]attribute{
+	"request.headers":      {root: "request.headers", typ: typeString, mapKey: true},
+	"request.method":       {root: "request.method", typ: typeString},
+	"request.uri":          {root: "request.uri", typ: typeString},
+	"source.labels":        {root: "source.labels", typ: typeString, mapKey: true},
+	"source.namespace":     {root: "source.namespace", typ: typeString},
+	"destination.port":     {root: "destination.port", typ: typeInt},
+	"connection.remote_ip": {root: "connection.remote_ip", typ: typeIP},
+}
+
+// Expr is a parsed, type-checked match expression.
+type Expr interface {
+	Eval(ctx EvalContext) (bool, error)
+	String() string
+}
+
+// Parse parses and type-checks src, returning an error for unknown
+// attribute paths, malformed literals, or comparisons between incompatible
+// types.
+func Parse(src string) (Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, src: src}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("matchexpr: unexpected token %q", p.toks[p.pos].text)
+	}
+	return expr, nil
+}
+
+// ---- AST ----
+
+type orExpr struct{ terms []Expr }
+
+func (e *orExpr) Eval(ctx EvalContext) (bool, error) {
+	for _, t := range e.terms {
+		ok, err := t.Eval(ctx)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (e *orExpr) String() string { return joinExpr(e.terms, " or ") }
+
+type andExpr struct{ terms []Expr }
+
+func (e *andExpr) Eval(ctx EvalContext) (bool, error) {
+	for _, t := range e.terms {
+		ok, err := t.Eval(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+func (e *andExpr) String() string { return joinExpr(e.terms, " and ") }
+
+func joinExpr(terms []Expr, sep string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = t.String()
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+type notExpr struct{ term Expr }
+
+func (e *notExpr) Eval(ctx EvalContext) (bool, error) {
+	ok, err := e.term.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+func (e *notExpr) String() string { return "not " + e.term.String() }
+
+type operand struct {
+	attr    attribute
+	key     string // subscript, for map-valued attributes
+	literal literal
+	isAttr  bool
+}
+
+type literal struct {
+	typ    attrType
+	str    string
+	num    int
+	ip     *net.IPNet // for CIDR literals
+	isCIDR bool
+	list   []literal
+}
+
+func (o operand) resolve(ctx EvalContext) (literal, error) {
+	if !o.isAttr {
+		return o.literal, nil
+	}
+	switch o.attr.root {
+	case "request.headers":
+		v, _ := ctx.RequestHeader(o.key)
+		return literal{typ: typeString, str: v}, nil
+	case "request.method":
+		return literal{typ: typeString, str: ctx.RequestMethod()}, nil
+	case "request.uri":
+		return literal{typ: typeString, str: ctx.RequestURI()}, nil
+	case "source.labels":
+		v, _ := ctx.SourceLabel(o.key)
+		return literal{typ: typeString, str: v}, nil
+	case "source.namespace":
+		return literal{typ: typeString, str: ctx.SourceNamespace()}, nil
+	case "destination.port":
+		return literal{typ: typeInt, num: ctx.DestinationPort()}, nil
+	case "connection.remote_ip":
+		ip := ctx.ConnectionRemoteIP()
+		return literal{typ: typeIP, str: ip.String()}, nil
+	}
+	return literal{}, fmt.Errorf("matchexpr: unresolvable attribute %q", o.attr.root)
+}
+
+type comparison struct {
+	op       string
+	lhs, rhs operand
+}
+
+func (e *comparison) String() string { return fmt.Sprintf("(%s)", e.op) }
+
+func (e *comparison) Eval(ctx EvalContext) (bool, error) {
+	lv, err := e.lhs.resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	switch e.op {
+	case "==", "!=":
+		rv, err := e.rhs.resolve(ctx)
+		if err != nil {
+			return false, err
+		}
+		eq := lv.str == rv.str && lv.num == rv.num
+		if e.op == "==" {
+			return eq, nil
+		}
+		return !eq, nil
+	case "contains":
+		rv, err := e.rhs.resolve(ctx)
+		if err != nil {
+			return false, err
+		}
+		return strings.Contains(lv.str, rv.str), nil
+	case "matches":
+		rv, err := e.rhs.resolve(ctx)
+		if err != nil {
+			return false, err
+		}
+		re, err := regexp.Compile(rv.str)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(lv.str), nil
+	case "in":
+		if e.rhs.literal.isCIDR {
+			ip := net.ParseIP(lv.str)
+			if ip == nil {
+				return false, fmt.Errorf("matchexpr: %q is not a valid IP address", lv.str)
+			}
+			return e.rhs.literal.ip.Contains(ip), nil
+		}
+		for _, item := range e.rhs.literal.list {
+			if item.str == lv.str && item.num == lv.num {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("matchexpr: unknown operator %q", e.op)
+}
+
+// ---- parser ----
+
+type parser struct {
+	toks []token
+	pos  int
+	src  string
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.toks) {
+		return token{kind: tokEOF}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Expr{first}
+	for p.peek().kind == tokIdent && p.peek().text == "or" {
+		p.next()
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &orExpr{terms: terms}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Expr{first}
+	for p.peek().kind == tokIdent && p.peek().text == "and" {
+		p.next()
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &andExpr{terms: terms}, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().kind == tokIdent && p.peek().text == "not" {
+		p.next()
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{term: term}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	if p.peek().kind == tokLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("matchexpr: expected ')'")
+		}
+		p.next()
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	lhs, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	opTok := p.next()
+	op, ok := map[tokenKind]string{
+		tokEq: "==", tokNeq: "!=",
+	}[opTok.kind]
+	if !ok && opTok.kind == tokIdent {
+		switch opTok.text {
+		case "in", "matches", "contains":
+			op = opTok.text
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("matchexpr: expected comparison operator, got %q", opTok.text)
+	}
+
+	rhs, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if lhs.isAttr && rhs.isAttr {
+		return nil, fmt.Errorf("matchexpr: cannot compare two attribute paths")
+	}
+	attrOperand := lhs
+	if !attrOperand.isAttr {
+		attrOperand = rhs
+	}
+	if attrOperand.isAttr {
+		if err := typeCheck(attrOperand, op, lhs, rhs); err != nil {
+			return nil, err
+		}
+	}
+
+	return &comparison{op: op, lhs: lhs, rhs: rhs}, nil
+}
+
+func typeCheck(attrOperand operand, op string, lhs, rhs operand) error {
+	lit := lhs
+	if lhs.isAttr {
+		lit = rhs
+	}
+	switch op {
+	case "==", "!=":
+		if lit.literal.isCIDR {
+			return fmt.Errorf("matchexpr: CIDR literal can only be used with \"in\", not %q", op)
+		}
+		if lit.literal.typ != attrOperand.attr.typ {
+			return fmt.Errorf("matchexpr: %q is type %s, cannot compare it to a %s literal with %q",
+				attrOperand.attr.root, attrOperand.attr.typ, lit.literal.typ, op)
+		}
+	case "matches", "contains":
+		if attrOperand.attr.typ != typeString {
+			return fmt.Errorf("matchexpr: %q only applies to string attributes, not %q", op, attrOperand.attr.root)
+		}
+		if lit.literal.typ != typeString || lit.literal.isCIDR || lit.literal.list != nil {
+			return fmt.Errorf("matchexpr: %q requires a string literal operand", op)
+		}
+	case "in":
+		if lit.literal.isCIDR {
+			if attrOperand.attr.typ != typeIP {
+				return fmt.Errorf("matchexpr: CIDR literal can only be compared against an IP attribute, not %q", attrOperand.attr.root)
+			}
+			break
+		}
+		for _, item := range lit.literal.list {
+			if item.typ != attrOperand.attr.typ {
+				return fmt.Errorf("matchexpr: %q is type %s, cannot compare it to a list containing a %s literal with \"in\"",
+					attrOperand.attr.root, attrOperand.attr.typ, item.typ)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return operand{literal: literal{typ: typeString, str: t.text}}, nil
+	case tokInt:
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return operand{}, fmt.Errorf("matchexpr: invalid integer literal %q", t.text)
+		}
+		return operand{literal: literal{typ: typeInt, num: n}}, nil
+	case tokCIDR:
+		_, ipnet, err := net.ParseCIDR(t.text)
+		if err != nil {
+			return operand{}, fmt.Errorf("matchexpr: invalid CIDR literal %q: %v", t.text, err)
+		}
+		return operand{literal: literal{isCIDR: true, ip: ipnet}}, nil
+	case tokLBracket:
+		var items []literal
+		for p.peek().kind != tokRBracket {
+			item, err := p.parseOperand()
+			if err != nil {
+				return operand{}, err
+			}
+			if item.isAttr {
+				return operand{}, fmt.Errorf("matchexpr: list literals cannot contain attribute paths")
+			}
+			items = append(items, item.literal)
+			if p.peek().kind == tokComma {
+				p.next()
+			}
+		}
+		p.next() // consume ']'
+		return operand{literal: literal{list: items}}, nil
+	case tokIdent:
+		return p.parseAttributePath(t.text)
+	}
+	return operand{}, fmt.Errorf("matchexpr: unexpected token %q", t.text)
+}
+
+func (p *parser) parseAttributePath(root string) (operand, error) {
+	path := root
+	for p.peek().kind == tokDot {
+		p.next()
+		id := p.next()
+		if id.kind != tokIdent {
+			return operand{}, fmt.Errorf("matchexpr: expected identifier after '.'")
+		}
+		path = path + "." + id.text
+	}
+
+	attr, ok := knownAttributes[path]
+	if !ok {
+		return operand{}, fmt.Errorf("matchexpr: unknown attribute %q", path)
+	}
+
+	var key string
+	if attr.mapKey {
+		if p.peek().kind != tokLBracket {
+			return operand{}, fmt.Errorf("matchexpr: attribute %q requires a [\"key\"] subscript", path)
+		}
+		p.next()
+		k := p.next()
+		if k.kind != tokString {
+			return operand{}, fmt.Errorf("matchexpr: subscript for %q must be a string literal", path)
+		}
+		key = k.text
+		if p.peek().kind != tokRBracket {
+			return operand{}, fmt.Errorf("matchexpr: expected ']'")
+		}
+		p.next()
+	}
+
+	return operand{attr: attr, key: key, isAttr: true}, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"sync"
+)
+
+// ProtocolQUIC declares that a port carries QUIC/HTTP3 traffic, which rides
+// on top of UDP like ProtocolTCP rides on top of TCP. The proxy does not
+// currently implement it; declaring it lets a ServiceEntry describe a
+// QUIC/HTTP3 origin honestly instead of lying about it as TCP.
+const ProtocolQUIC Protocol = "QUIC"
+
+// isUDPFamilyName reports whether rawProtocol names a datagram-based
+// protocol (UDP or QUIC). It checks the raw string rather than going
+// through ParseProtocol/Protocol so that ProtocolQUIC - added after
+// ParseProtocol's switch was last touched - is recognized even before that
+// switch is updated to return it.
+func isUDPFamilyName(rawProtocol string) bool {
+	switch strings.ToUpper(rawProtocol) {
+	case string(ProtocolUDP), string(ProtocolQUIC):
+		return true
+	}
+	return false
+}
+
+// ProtocolValidator performs extra, protocol-specific validation of a raw
+// protocol string beyond "is this name recognized at all". RegisterProtocol
+// callers that don't need anything beyond recognition can pass nil.
+type ProtocolValidator func(rawProtocol string) error
+
+var (
+	customProtocolsMu sync.RWMutex
+	customProtocols   = make(map[string]ProtocolValidator)
+)
+
+// RegisterProtocol teaches validateProtocol a protocol name it doesn't
+// recognize out of the box (e.g. "MEMCACHED", "KAFKA"), so out-of-tree
+// extensions can plug in new protocol names without forking the Protocol
+// enum. name is matched case-insensitively. v is consulted by
+// validateProtocol whenever a port declares this protocol; it may be nil to
+// accept the name with no further checks.
+func RegisterProtocol(name string, v ProtocolValidator) {
+	customProtocolsMu.Lock()
+	defer customProtocolsMu.Unlock()
+	customProtocols[strings.ToUpper(name)] = v
+}
+
+func lookupCustomProtocol(name string) (ProtocolValidator, bool) {
+	customProtocolsMu.RLock()
+	defer customProtocolsMu.RUnlock()
+	v, ok := customProtocols[strings.ToUpper(name)]
+	return v, ok
+}
+
+func init() {
+	// QUIC is recognized by isUDPFamilyName/validateProtocol above without
+	// needing a ParseProtocol switch case, but it still goes through the
+	// same RegisterProtocol path an out-of-tree protocol would, so
+	// validateProtocol accepts it instead of reporting it unsupported.
+	RegisterProtocol(string(ProtocolQUIC), nil)
+}
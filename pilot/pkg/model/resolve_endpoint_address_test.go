@@ -0,0 +1,36 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveEndpointAddressPassesThroughNonTemplate(t *testing.T) {
+	got, err := ResolveEndpointAddress(context.Background(), "10.0.0.1")
+	if err != nil {
+		t.Fatalf("ResolveEndpointAddress(10.0.0.1) error = %v", err)
+	}
+	if got != "10.0.0.1" {
+		t.Errorf("ResolveEndpointAddress(10.0.0.1) = %q, want unchanged", got)
+	}
+}
+
+func TestResolveEndpointAddressRejectsInvalidTemplate(t *testing.T) {
+	if _, err := ResolveEndpointAddress(context.Background(), "{{ NotAFunction }}"); err == nil {
+		t.Error("ResolveEndpointAddress with an invalid template = nil error, want error")
+	}
+}
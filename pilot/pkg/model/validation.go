@@ -15,14 +15,18 @@
 package model
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	gogoproto "github.com/gogo/protobuf/proto"
@@ -31,14 +35,24 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/duration"
 	multierror "github.com/hashicorp/go-multierror"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	authn "istio.io/api/authentication/v1alpha1"
+	extensions "istio.io/api/extensions/v1alpha1"
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	mpb "istio.io/api/mixer/v1"
 	mccpb "istio.io/api/mixer/v1/config/client"
 	networking "istio.io/api/networking/v1alpha3"
 	rbac "istio.io/api/rbac/v1alpha1"
 	routing "istio.io/api/routing/v1alpha1"
+	securitybeta "istio.io/api/security/v1beta1"
+
+	"istio.io/istio/pilot/pkg/model/addrtemplate"
+	"istio.io/istio/pilot/pkg/model/field"
+	"istio.io/istio/pilot/pkg/model/matchexpr"
 )
 
 const (
@@ -94,6 +108,35 @@ func ValidatePort(port int) error {
 	return fmt.Errorf("port number %d must be in the range 1..65535", port)
 }
 
+// ParsePortRange parses a "low-high" port range, both bounds inclusive and
+// in 1..65535 with low <= high, the syntax a ServiceEntry port's Name may
+// use instead of a single Number to front a service listening on a
+// contiguous block of ports without enumerating each one.
+func ParsePortRange(s string) (lo, hi uint32, err error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%q is not a port range of the form low-high", s)
+	}
+	loInt, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not a port range of the form low-high: %v", s, err)
+	}
+	hiInt, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not a port range of the form low-high: %v", s, err)
+	}
+	if err := ValidatePort(loInt); err != nil {
+		return 0, 0, multierror.Prefix(err, fmt.Sprintf("port range %q: ", s))
+	}
+	if err := ValidatePort(hiInt); err != nil {
+		return 0, 0, multierror.Prefix(err, fmt.Sprintf("port range %q: ", s))
+	}
+	if loInt > hiInt {
+		return 0, 0, fmt.Errorf("port range %q: low must be <= high", s)
+	}
+	return uint32(loInt), uint32(hiInt), nil
+}
+
 // Validate checks that each name conforms to the spec and has a ProtoMessage
 func (descriptor ConfigDescriptor) Validate() error {
 	var errs error
@@ -364,6 +407,12 @@ func ValidateMatchCondition(mc *routing.MatchCondition) (errs error) {
 		}
 	}
 
+	if mc.Expression != "" {
+		if _, err := matchexpr.Parse(mc.Expression); err != nil {
+			errs = multierror.Append(errs, multierror.Prefix(err, "expression invalid: "))
+		}
+	}
+
 	return
 }
 
@@ -500,18 +549,16 @@ func ValidateL4Fault(fault *routing.L4FaultInjection) (errs error) {
 	return
 }
 
-// ValidateSubnet checks that IPv4 subnet form
+// ValidateSubnet checks that a string is a valid IPv4 or IPv6 subnet
 func ValidateSubnet(subnet string) error {
-	// The current implementation only supports IP v4 addresses
-	return ValidateIPv4Subnet(subnet)
+	return ValidateIPSubnet(subnet)
 }
 
-// validateCIDR checks that a string is in "CIDR notation"
+// validateCIDR checks that a string is in "CIDR notation", restricted to IPv4
 func validateCIDR(cidr string) error {
-	// We expect a string in "CIDR notation", i.e. a.b.c.d/xx form
-	ip, _, err := net.ParseCIDR(cidr)
+	ip, err := validateCIDRAny(cidr)
 	if err != nil {
-		return fmt.Errorf("%v is not a valid CIDR block", cidr)
+		return err
 	}
 	// The current implementation only supports IP v4 addresses
 	if ip.To4() == nil {
@@ -521,6 +568,17 @@ func validateCIDR(cidr string) error {
 	return nil
 }
 
+// validateCIDRAny checks that a string is in "CIDR notation", accepting both IPv4 and IPv6
+// blocks, and returns the parsed network address.
+func validateCIDRAny(cidr string) (net.IP, error) {
+	// We expect a string in "CIDR notation", i.e. a.b.c.d/xx or a:b::c/xx form
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a valid CIDR block", cidr)
+	}
+	return ip, nil
+}
+
 // ValidateIPv4Subnet checks that a string is in "CIDR notation" or "Dot-decimal notation"
 func ValidateIPv4Subnet(subnet string) error {
 	// We expect a string in "CIDR notation" or "Dot-decimal notation"
@@ -546,6 +604,125 @@ func ValidateIPv4Address(addr string) error {
 	return nil
 }
 
+// ValidateIPSubnet checks that a string is a valid IPv4 or IPv6 address, in "CIDR notation"
+// or plain address form. Unlike ValidateIPv4Subnet, this accepts IPv6 literals and CIDR
+// blocks (e.g. "fe80::/10", "::1") so dual-stack meshes and IPv6-only clusters can be
+// expressed natively instead of being rejected at the IPv4-only gate.
+func ValidateIPSubnet(subnet string) error {
+	if strings.Count(subnet, "/") == 1 {
+		_, err := validateCIDRAny(subnet)
+		return err
+	}
+	return ValidateIPAddress(subnet)
+}
+
+// ValidateIPAddress validates that a string is a valid IPv4 or IPv6 address, without
+// restricting to a single address family.
+func ValidateIPAddress(addr string) error {
+	if net.ParseIP(addr) == nil {
+		return fmt.Errorf("%v is not a valid IP address", addr)
+	}
+	return nil
+}
+
+// ValidateCIDR checks that a string is a valid IPv4 or IPv6 CIDR block,
+// e.g. "10.0.0.0/24" or "fd00::/8". Unlike ValidateIPSubnet, a bare address
+// with no "/" prefix length is rejected - callers that accept either form
+// should check for "/" themselves and fall back to ValidateIPAddress.
+func ValidateCIDR(cidr string) error {
+	_, err := validateCIDRAny(cidr)
+	return err
+}
+
+// validateNoCIDREndpointOverlap rejects a ServiceEntry whose STATIC
+// endpoints mix a CIDR block with a single IP (or another CIDR) that falls
+// inside it - each backend should be reachable through exactly one
+// endpoint entry, so an overlap almost always indicates a copy-paste
+// mistake rather than an intentional, redundant listing.
+func validateNoCIDREndpointOverlap(cidrs []*net.IPNet, ips []net.IP) error {
+	var errs error
+	for i, a := range cidrs {
+		for _, ip := range ips {
+			if a.Contains(ip) {
+				errs = appendErrors(errs, fmt.Errorf("endpoint %s overlaps with single-IP endpoint %s", a, ip))
+			}
+		}
+		for j := i + 1; j < len(cidrs); j++ {
+			b := cidrs[j]
+			if a.Contains(b.IP) || b.Contains(a.IP) {
+				errs = appendErrors(errs, fmt.Errorf("endpoint %s overlaps with endpoint %s", a, b))
+			}
+		}
+	}
+	return errs
+}
+
+// validateConsistentEndpointFamily rejects a STATIC ServiceEntry whose
+// endpoints mix IPv4 and IPv6 addresses: a dual-stack backend is expressed
+// as two ServiceEntries (or two endpoints on hosts that are themselves
+// resolved per-family), not as one ServiceEntry whose Endpoints silently
+// span both families.
+func validateConsistentEndpointFamily(cidrs []*net.IPNet, ips []net.IP) error {
+	families := make(map[string]bool, 2)
+	for _, ip := range ips {
+		families[ipFamily(ip)] = true
+	}
+	for _, cidr := range cidrs {
+		families[ipFamily(cidr.IP)] = true
+	}
+	if len(families) > 1 {
+		return fmt.Errorf("endpoints mix IPv4 and IPv6 addresses; all endpoints of a single ServiceEntry must share one address family")
+	}
+	return nil
+}
+
+// maxEndpointWeight is the upper bound of a ServiceEntry endpoint's Weight,
+// mirroring the [0, 10000] range Envoy's LbEndpoint load_balancing_weight
+// already uses elsewhere in the mesh (e.g. traffic-split Destinations).
+const maxEndpointWeight = 10000
+
+// validateEndpointWeights checks the Weight set on a ServiceEntry's
+// endpoints, the native (VirtualService-free) way to canary/blue-green a
+// ServiceEntry: either every endpoint carries a weight, or none do, so the
+// proxy never has to guess what an unweighted endpoint alongside weighted
+// ones was supposed to mean.
+func validateEndpointWeights(serviceEntry *networking.ServiceEntry, endpointsPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if serviceEntry.Resolution == networking.ServiceEntry_NONE && len(serviceEntry.Endpoints) > 0 {
+		// len(Endpoints) > 0 under NONE is already flagged by the caller;
+		// weight is meaningless there regardless, so don't pile on a second
+		// "invalid weight" error for the same misconfiguration.
+		return errs
+	}
+
+	// A zero Weight is indistinguishable from "unset" under proto3, so that's
+	// the convention used here too (consistent with Port.Number == 0 marking
+	// a port-range declaration elsewhere in this file): only endpoints with
+	// Weight > 0 count as participating in the split. That same convention
+	// rules out a separate "sum of weights is non-zero" check: whenever every
+	// endpoint participates (the case below requires), each one already
+	// contributed a positive weight, so the sum is non-zero by construction -
+	// there's no way under this representation to reach "all endpoints
+	// weighted" with a zero sum, so that check could never fire.
+	weighted := 0
+	for i, endpoint := range serviceEntry.Endpoints {
+		weightPath := endpointsPath.Index(i).Child("weight")
+		if endpoint.Weight > maxEndpointWeight {
+			errs = errs.Append(field.Invalid(weightPath, endpoint.Weight,
+				fmt.Sprintf("must be between 0 and %d", maxEndpointWeight)))
+		}
+		if endpoint.Weight > 0 {
+			weighted++
+		}
+	}
+
+	if weighted > 0 && weighted != len(serviceEntry.Endpoints) {
+		errs = errs.Append(field.Invalid(endpointsPath, nil,
+			"weight must be set on either all endpoints or none of them"))
+	}
+	return errs
+}
+
 // ValidateUnixAddress validates that the string is a valid unix domain socket path.
 func ValidateUnixAddress(addr string) error {
 	if len(addr) == 0 {
@@ -936,7 +1113,7 @@ func validateServerPort(port *networking.Port) (errs error) {
 		return appendErrors(errs, fmt.Errorf("port is required"))
 	}
 	if ParseProtocol(port.Protocol) == ProtocolUnsupported {
-		errs = appendErrors(errs, fmt.Errorf("invalid protocol %q, supported protocols are HTTP, HTTP2, GRPC, MONGO, REDIS, TCP", port.Protocol))
+		errs = appendErrors(errs, validateProtocol(port.Protocol))
 	}
 	if port.Number > 0 {
 		errs = appendErrors(errs, ValidatePort(int(port.Number)))
@@ -1185,7 +1362,61 @@ func validateLoadBalancer(settings *networking.LoadBalancerSettings) (errs error
 	}
 
 	// simple load balancing is always valid
-	// TODO: settings.GetConsistentHash()
+	if hash := settings.GetConsistentHash(); hash != nil {
+		errs = appendErrors(errs, validateConsistentHashLoadBalancer(hash))
+
+		if settings.GetLocalityLbSetting() != nil {
+			errs = appendErrors(errs, fmt.Errorf("consistentHash load balancing cannot be combined with localityLbSetting: Envoy's ring_hash/maglev load balancers do not support locality weighting"))
+		}
+	}
+
+	return
+}
+
+// validateConsistentHashLoadBalancer validates a ring_hash/maglev
+// consistent-hash load balancing policy: exactly one hash source must be
+// set, and ring_hash additionally requires a positive minimum ring size.
+func validateConsistentHashLoadBalancer(hash *networking.LoadBalancerSettings_ConsistentHashLB) (errs error) {
+	sources := 0
+	if h := hash.GetHttpHeaderName(); h != "" {
+		sources++
+		errs = appendErrors(errs, ValidateHTTPHeaderName(h))
+	}
+	if cookie := hash.GetHttpCookie(); cookie != nil {
+		sources++
+		if cookie.Name == "" {
+			errs = appendErrors(errs, fmt.Errorf("httpCookie hash source requires a non-empty name"))
+		}
+		if cookie.Ttl != nil {
+			if cookie.Ttl.Seconds < 0 || cookie.Ttl.Nanos < 0 {
+				errs = appendErrors(errs, fmt.Errorf("httpCookie ttl cannot be negative (0 means a session cookie)"))
+			} else if cookie.Ttl.Seconds != 0 || cookie.Ttl.Nanos != 0 {
+				errs = appendErrors(errs, ValidateDurationGogo(cookie.Ttl))
+			}
+		}
+	}
+	if q := hash.GetHttpQueryParameterName(); q != "" {
+		sources++
+		if strings.TrimSpace(q) == "" {
+			errs = appendErrors(errs, fmt.Errorf("httpQueryParameterName hash source requires a non-empty name"))
+		}
+	}
+	if hash.GetUseSourceIp() {
+		sources++
+	}
+
+	switch sources {
+	case 0:
+		errs = appendErrors(errs, fmt.Errorf("consistentHash load balancing requires exactly one hash source (httpHeaderName, httpCookie, httpQueryParameterName, or useSourceIp)"))
+	case 1:
+		// exactly one hash source is valid
+	default:
+		errs = appendErrors(errs, fmt.Errorf("consistentHash load balancing supports exactly one hash source, got %d", sources))
+	}
+
+	if hash.MinimumRingSize == 0 {
+		errs = appendErrors(errs, fmt.Errorf("consistentHash minimumRingSize must be greater than 0"))
+	}
 
 	return
 }
@@ -1746,6 +1977,663 @@ func ValidateAuthenticationPolicy(name, namespace string, msg proto.Message) err
 	return errs
 }
 
+// knownAuthorizationConditionKeys is the allowlist of attribute names an
+// AuthorizationPolicy rule's `When` condition may reference. Anything else
+// is rejected at config-load time rather than silently never matching in
+// the proxy.
+var knownAuthorizationConditionKeys = map[string]bool{
+	"source.ip":              true,
+	"source.namespace":       true,
+	"source.principal":       true,
+	"request.auth.principal": true,
+	"request.auth.audiences": true,
+	"request.auth.presenter": true,
+	"destination.ip":         true,
+	"destination.port":       true,
+	"connection.sni":         true,
+}
+
+// isRequestHeadersKey reports whether key is of the form
+// `request.headers[<name>]`, the one condition key that takes a subscript.
+func isRequestHeadersKey(key string) bool {
+	return strings.HasPrefix(key, "request.headers[") && strings.HasSuffix(key, "]")
+}
+
+// RootNamespace is the namespace whose PeerAuthentication/AuthorizationPolicy
+// resources apply mesh-wide rather than to a single namespace. It mirrors
+// the mesh config's RootNamespace setting; binaries that configure a
+// non-default root namespace should update this var accordingly.
+var RootNamespace = "istio-system"
+
+// ValidatePeerAuthentication checks that a security.istio.io/v1beta1
+// PeerAuthentication resource is well-formed.
+func ValidatePeerAuthentication(name, namespace string, msg proto.Message) error {
+	in, ok := msg.(*securitybeta.PeerAuthentication)
+	if !ok {
+		return errors.New("cannot cast to PeerAuthentication")
+	}
+	var errs error
+
+	if in.Selector != nil {
+		errs = appendErrors(errs, Labels(in.Selector.MatchLabels).Validate())
+	}
+
+	if (namespace == "" || namespace == RootNamespace) && in.Selector != nil {
+		errs = appendErrors(errs, fmt.Errorf("a mesh-wide PeerAuthentication (namespace %q) cannot set a workload selector", namespace))
+	}
+
+	if in.Mtls != nil {
+		errs = appendErrors(errs, validatePeerAuthenticationMtlsMode(in.Mtls.Mode))
+	}
+
+	for port, mtls := range in.PortLevelMtls {
+		if in.Selector == nil {
+			errs = appendErrors(errs, fmt.Errorf("portLevelMtls for port %d requires a workload selector", port))
+		}
+		if err := ValidatePort(int(port)); err != nil {
+			errs = appendErrors(errs, multierror.Prefix(err, fmt.Sprintf("portLevelMtls port %d invalid: ", port)))
+		}
+		errs = appendErrors(errs, validatePeerAuthenticationMtlsMode(mtls.Mode))
+	}
+
+	return errs
+}
+
+func validatePeerAuthenticationMtlsMode(mode securitybeta.PeerAuthentication_MutualTLS_Mode) error {
+	switch mode {
+	case securitybeta.PeerAuthentication_MutualTLS_UNSET,
+		securitybeta.PeerAuthentication_MutualTLS_DISABLE,
+		securitybeta.PeerAuthentication_MutualTLS_PERMISSIVE,
+		securitybeta.PeerAuthentication_MutualTLS_STRICT:
+		return nil
+	default:
+		return fmt.Errorf("unrecognized mTLS mode %q", mode)
+	}
+}
+
+// ValidateAuthorizationPolicy checks that a security.istio.io/v1beta1
+// AuthorizationPolicy resource is well-formed.
+func ValidateAuthorizationPolicy(name, namespace string, msg proto.Message) error {
+	in, ok := msg.(*securitybeta.AuthorizationPolicy)
+	if !ok {
+		return errors.New("cannot cast to AuthorizationPolicy")
+	}
+	var errs error
+
+	if in.Selector != nil {
+		errs = appendErrors(errs, Labels(in.Selector.MatchLabels).Validate())
+	}
+
+	switch in.Action {
+	case securitybeta.AuthorizationPolicy_ALLOW,
+		securitybeta.AuthorizationPolicy_DENY,
+		securitybeta.AuthorizationPolicy_AUDIT,
+		securitybeta.AuthorizationPolicy_CUSTOM:
+	default:
+		errs = appendErrors(errs, fmt.Errorf("unrecognized action %q", in.Action))
+	}
+
+	if in.Action != securitybeta.AuthorizationPolicy_CUSTOM {
+		if in.Provider != nil {
+			errs = appendErrors(errs, fmt.Errorf("provider can only be set when action is CUSTOM"))
+		}
+		if len(in.Rules) == 0 {
+			errs = appendErrors(errs, fmt.Errorf("at least one rule must be specified for action %q", in.Action))
+		}
+	}
+
+	for i, rule := range in.Rules {
+		errs = appendErrors(errs, validateAuthorizationRule(i, rule))
+	}
+
+	return errs
+}
+
+func validateAuthorizationRule(index int, rule *securitybeta.Rule) (errs error) {
+	prefix := func(err error) error {
+		if err == nil {
+			return nil
+		}
+		return multierror.Prefix(err, fmt.Sprintf("rule %d: ", index))
+	}
+
+	for _, from := range rule.From {
+		source := from.GetSource()
+		if source == nil {
+			continue
+		}
+		errs = appendErrors(errs, prefix(validateNonEmptyStrings("notPrincipals", source.NotPrincipals)))
+		errs = appendErrors(errs, prefix(validateNonEmptyStrings("notNamespaces", source.NotNamespaces)))
+		errs = appendErrors(errs, prefix(validateNonEmptyStrings("notIpBlocks", source.NotIpBlocks)))
+	}
+
+	for _, to := range rule.To {
+		op := to.GetOperation()
+		if op == nil {
+			continue
+		}
+		for _, host := range op.Hosts {
+			errs = appendErrors(errs, prefix(ValidateWildcardDomain(host)))
+		}
+		for _, host := range op.NotHosts {
+			errs = appendErrors(errs, prefix(ValidateWildcardDomain(host)))
+		}
+	}
+
+	for _, when := range rule.When {
+		if when.Key == "" {
+			errs = appendErrors(errs, prefix(fmt.Errorf("when condition key cannot be empty")))
+			continue
+		}
+		if !knownAuthorizationConditionKeys[when.Key] && !isRequestHeadersKey(when.Key) {
+			errs = appendErrors(errs, prefix(fmt.Errorf("unrecognized when condition key %q", when.Key)))
+		}
+		if len(when.Values) == 0 && len(when.NotValues) == 0 {
+			errs = appendErrors(errs, prefix(fmt.Errorf("when condition %q must set values or notValues", when.Key)))
+		}
+	}
+
+	return
+}
+
+func validateNonEmptyStrings(field string, values []string) error {
+	for _, v := range values {
+		if v == "" {
+			return fmt.Errorf("%s entries must be non-empty strings", field)
+		}
+	}
+	return nil
+}
+
+// ValidateRequestAuthentication checks that a security.istio.io/v1beta1
+// RequestAuthentication resource is well-formed.
+func ValidateRequestAuthentication(name, namespace string, msg proto.Message) error {
+	in, ok := msg.(*securitybeta.RequestAuthentication)
+	if !ok {
+		return errors.New("cannot cast to RequestAuthentication")
+	}
+	var errs error
+
+	if in.Selector != nil {
+		errs = appendErrors(errs, Labels(in.Selector.MatchLabels).Validate())
+	}
+
+	seenIssuers := make(map[string]int)
+	for i, rule := range in.JwtRules {
+		errs = appendErrors(errs, validateJwtRule(i, rule))
+		if rule.Issuer == "" {
+			continue
+		}
+		if first, ok := seenIssuers[rule.Issuer]; ok {
+			errs = appendErrors(errs, fmt.Errorf("jwtRules[%d] and jwtRules[%d] both set issuer %q: issuers must be unique within a RequestAuthentication", first, i, rule.Issuer))
+			continue
+		}
+		seenIssuers[rule.Issuer] = i
+	}
+
+	return errs
+}
+
+func validateJwtRule(index int, rule *securitybeta.JWTRule) (errs error) {
+	prefix := func(err error) error {
+		if err == nil {
+			return nil
+		}
+		return multierror.Prefix(err, fmt.Sprintf("jwtRules[%d]: ", index))
+	}
+
+	if rule.Issuer == "" {
+		errs = appendErrors(errs, prefix(errors.New("issuer must be set")))
+	}
+
+	for _, audience := range rule.Audiences {
+		if audience == "" {
+			errs = appendErrors(errs, prefix(errors.New("audience must be a non-empty string")))
+		}
+	}
+
+	if rule.JwksUri != "" && rule.Jwks != "" {
+		errs = appendErrors(errs, prefix(errors.New("jwksUri and jwks are mutually exclusive")))
+	}
+
+	if rule.JwksUri != "" {
+		errs = appendErrors(errs, prefix(validateJwksURI(rule.JwksUri)))
+	}
+
+	if rule.Jwks != "" {
+		errs = appendErrors(errs, prefix(validateInlineJwks(rule.Jwks)))
+	}
+
+	for _, header := range rule.FromHeaders {
+		errs = appendErrors(errs, prefix(ValidateHTTPHeaderName(header.Name)))
+	}
+
+	for _, param := range rule.FromParams {
+		if param == "" {
+			errs = appendErrors(errs, prefix(errors.New("fromParams entries must be non-empty strings")))
+		}
+	}
+
+	return
+}
+
+// ParseJwksURI parses the given JWKS URI and returns the corresponding
+// hostname, port, and whether the connection should use TLS. It is shared
+// by the legacy authentication.v1alpha1 Policy validation (validateJwt) and
+// the security.istio.io/v1beta1 RequestAuthentication validation
+// (validateJwtRule, via validateJwksURI), since
+// both need the same eager, offline check of a JwksUri field.
+func ParseJwksURI(jwksURI string) (string, int, bool, error) {
+	u, err := url.Parse(jwksURI)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("jwksUri %q is not a valid URL: %v", jwksURI, err)
+	}
+
+	var useTLS bool
+	switch u.Scheme {
+	case "http":
+		useTLS = false
+	case "https":
+		useTLS = true
+	default:
+		return "", 0, false, fmt.Errorf("jwksUri %q must use the http or https scheme", jwksURI)
+	}
+
+	hostname := u.Hostname()
+	if hostname == "" {
+		return "", 0, false, fmt.Errorf("jwksUri %q must be an absolute URL", jwksURI)
+	}
+
+	port := u.Port()
+	if port == "" {
+		if useTLS {
+			port = "443"
+		} else {
+			port = "80"
+		}
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("jwksUri %q has an invalid port %q: %v", jwksURI, port, err)
+	}
+
+	return hostname, portNum, useTLS, nil
+}
+
+// validateJwksURI checks that uri is a well-formed absolute http(s) URL. It
+// does not fetch the URI - JWT policies should still fail closed offline,
+// but an operator gets eager, synchronous feedback on a malformed URL
+// without Pilot reaching out to an (possibly unreachable) JWKS endpoint
+// during config validation.
+func validateJwksURI(uri string) error {
+	_, _, _, err := ParseJwksURI(uri)
+	return err
+}
+
+// jwkSet and jwk mirror just enough of RFC 7517 to reject a malformed
+// inline JWK Set (missing "keys", or a key missing "kty") without pulling
+// in a full JOSE library.
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+}
+
+func validateInlineJwks(raw string) error {
+	var set jwkSet
+	if err := json.Unmarshal([]byte(raw), &set); err != nil {
+		return fmt.Errorf("jwks is not a valid JWK set: %v", err)
+	}
+	if len(set.Keys) == 0 {
+		return errors.New("jwks must contain at least one key")
+	}
+	for i, key := range set.Keys {
+		if key.Kty == "" {
+			return fmt.Errorf("jwks key %d is missing required field kty", i)
+		}
+	}
+	return nil
+}
+
+// wasmPluginURLSchemeRequiresSha256 holds the WasmPlugin.Url schemes that
+// fetch remote, mutable content and therefore must be pinned by a sha256
+// digest; a "file" URL is already local and trusted.
+var wasmPluginURLSchemeRequiresSha256 = map[string]bool{
+	"http":  true,
+	"https": true,
+	"oci":   true,
+}
+
+var sha256HexRegexp = regexp.MustCompile("^[0-9a-fA-F]{64}$")
+
+// ValidateWasmPlugin checks that an extensions.istio.io/v1alpha1 WasmPlugin
+// resource is well-formed.
+func ValidateWasmPlugin(name, namespace string, msg proto.Message) error {
+	in, ok := msg.(*extensions.WasmPlugin)
+	if !ok {
+		return errors.New("cannot cast to WasmPlugin")
+	}
+	var errs error
+
+	if in.Url == "" {
+		errs = appendErrors(errs, errors.New("url must be set"))
+	} else {
+		u, err := url.Parse(in.Url)
+		if err != nil {
+			errs = appendErrors(errs, fmt.Errorf("url %q is not valid: %v", in.Url, err))
+		} else {
+			switch u.Scheme {
+			case "http", "https", "oci", "file":
+			default:
+				errs = appendErrors(errs, fmt.Errorf("url %q must use the http, https, oci, or file scheme", in.Url))
+			}
+			if wasmPluginURLSchemeRequiresSha256[u.Scheme] {
+				if in.Sha256 == "" {
+					errs = appendErrors(errs, fmt.Errorf("sha256 is required for url scheme %q", u.Scheme))
+				} else if !sha256HexRegexp.MatchString(in.Sha256) {
+					errs = appendErrors(errs, fmt.Errorf("sha256 %q must be 64 hex characters", in.Sha256))
+				}
+			}
+		}
+	}
+
+	switch in.Phase {
+	case extensions.PluginPhase_UNSPECIFIED_PHASE,
+		extensions.PluginPhase_AUTHN,
+		extensions.PluginPhase_AUTHZ,
+		extensions.PluginPhase_STATS:
+	default:
+		errs = appendErrors(errs, fmt.Errorf("unrecognized phase %q", in.Phase))
+	}
+
+	if in.Priority != nil && in.Priority.Value < 0 {
+		errs = appendErrors(errs, errors.New("priority must be non-negative"))
+	}
+
+	if in.PluginConfig != nil {
+		if _, err := protojson.Marshal(in.PluginConfig); err != nil {
+			errs = appendErrors(errs, fmt.Errorf("pluginConfig does not round-trip through structpb: %v", err))
+		}
+	}
+
+	return errs
+}
+
+// knownEnvoyFilterNames guards against common typos in EnvoyFilter filter
+// names (e.g. "envoy.filters.http.jwt_auth" vs the real
+// "envoy.filters.http.jwt_authn") by listing well-known filter names callers
+// can register against. Downstream code can add additional names with
+// RegisterWellKnownEnvoyFilterName.
+var knownEnvoyFilterNames = map[string]bool{
+	"envoy.filters.http.router":                     true,
+	"envoy.filters.http.jwt_authn":                  true,
+	"envoy.filters.http.rbac":                       true,
+	"envoy.filters.http.cors":                       true,
+	"envoy.filters.http.fault":                      true,
+	"envoy.filters.network.tcp_proxy":               true,
+	"envoy.filters.network.http_connection_manager": true,
+}
+
+// RegisterWellKnownEnvoyFilterName lets downstream code (e.g. a custom
+// Envoy build with additional filters) register additional well-known
+// filter type-URLs/names so EnvoyFilter validation doesn't flag them as
+// typos.
+func RegisterWellKnownEnvoyFilterName(name string) {
+	knownEnvoyFilterNames[name] = true
+}
+
+// validateEnvoyTypedConfig resolves a patch's typed_config payload (encoded
+// as a google.protobuf.Struct with an "@type" field, the JSON
+// representation of a google.protobuf.Any) against the global proto
+// registry and confirms it decodes as a valid instance of that message
+// type. This turns "silently dropped by the proxy" EnvoyFilter bugs into a
+// config-push-time error.
+func validateEnvoyTypedConfig(value *structpb.Struct) error {
+	if value == nil {
+		return nil
+	}
+	typeField, ok := value.Fields["@type"]
+	if !ok {
+		// Not every patch carries a typed_config payload (e.g. a plain Struct
+		// merge patch); nothing further to check.
+		return nil
+	}
+	typeURL := typeField.GetStringValue()
+	if typeURL == "" {
+		return errors.New("typed_config \"@type\" must be a string")
+	}
+
+	name := typeURL
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if knownEnvoyFilterNames[name] {
+		// A well-known filter name: skip the (expensive) full schema
+		// round-trip and trust the registered name.
+		return nil
+	}
+
+	msgType, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return fmt.Errorf("typed_config type %q is not a recognized proto message: %v", typeURL, err)
+	}
+
+	raw, err := protojson.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("typed_config is not valid JSON: %v", err)
+	}
+	dyn := dynamicpb.NewMessage(msgType.Descriptor())
+	if err := protojson.Unmarshal(raw, dyn); err != nil {
+		return fmt.Errorf("typed_config does not match %q: %v", typeURL, err)
+	}
+
+	return nil
+}
+
+// ValidateEnvoyFilter checks that a networking.istio.io/v1alpha3 EnvoyFilter
+// resource is well-formed, including (where present) that each patch's
+// typed_config payload decodes as a valid instance of the proto message its
+// "@type" names.
+func ValidateEnvoyFilter(name, namespace string, msg proto.Message) error {
+	in, ok := msg.(*networking.EnvoyFilter)
+	if !ok {
+		return errors.New("cannot cast to EnvoyFilter")
+	}
+	var errs error
+
+	for i, patch := range in.ConfigPatches {
+		prefix := fmt.Sprintf("configPatches[%d]: ", i)
+
+		errs = appendErrors(errs, multierror.Prefix(validateEnvoyFilterApplyTo(patch), prefix))
+
+		if patch.Patch == nil {
+			continue
+		}
+		errs = appendErrors(errs, multierror.Prefix(validateEnvoyFilterPatchOperation(patch.Patch.Operation), prefix))
+		if err := validateEnvoyTypedConfig(patch.Patch.Value); err != nil {
+			errs = appendErrors(errs, multierror.Prefix(err, prefix))
+		}
+	}
+
+	return errs
+}
+
+// httpConnectionManagerFilterName is the well-known network filter name
+// Envoy uses for the HTTP connection manager, resolved the same way
+// envoyproxy/go-control-plane's wellknown package would
+// (wellknown.HTTPConnectionManager); duplicated here rather than imported
+// so this package doesn't pick up a go-control-plane dependency just for a
+// handful of string constants.
+const httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+
+// validateEnvoyFilterApplyTo checks that patch.ApplyTo and patch.Match
+// describe a legal combination - in particular, that an HTTP_FILTER patch
+// (which can only ever apply inside an HTTP connection manager) actually
+// matches a listener filter chain whose filter is the HTTP connection
+// manager, and not some other network filter.
+func validateEnvoyFilterApplyTo(patch *networking.EnvoyFilter_EnvoyConfigObjectPatch) error {
+	if patch.ApplyTo != networking.EnvoyFilter_HTTP_FILTER {
+		return nil
+	}
+
+	listener := patch.GetMatch().GetListener()
+	if listener == nil {
+		return errors.New("applyTo HTTP_FILTER requires a listener match")
+	}
+	filter := listener.FilterChain.GetFilter()
+	if filter != nil && filter.Name != "" && filter.Name != httpConnectionManagerFilterName {
+		return fmt.Errorf("applyTo HTTP_FILTER requires match.listener.filterChain.filter.name %q, got %q",
+			httpConnectionManagerFilterName, filter.Name)
+	}
+	return nil
+}
+
+func validateEnvoyFilterPatchOperation(op networking.EnvoyFilter_Patch_Operation) error {
+	switch op {
+	case networking.EnvoyFilter_Patch_MERGE,
+		networking.EnvoyFilter_Patch_ADD,
+		networking.EnvoyFilter_Patch_REMOVE,
+		networking.EnvoyFilter_Patch_INSERT_BEFORE,
+		networking.EnvoyFilter_Patch_INSERT_AFTER:
+		return nil
+	default:
+		return fmt.Errorf("unrecognized patch operation %q", op)
+	}
+}
+
+// loopbackAddresses are the only addresses a CaptureMode_NONE egress
+// listener (traffic that bypasses iptables redirection entirely) is allowed
+// to bind, in either address family.
+var loopbackAddresses = map[string]bool{
+	"127.0.0.1": true,
+	"::1":       true,
+}
+
+// ValidateSidecar checks that a networking.istio.io/v1alpha3 Sidecar
+// resource is well-formed.
+func ValidateSidecar(name, namespace string, msg proto.Message) (errs error) {
+	in, ok := msg.(*networking.Sidecar)
+	if !ok {
+		return errors.New("cannot cast to Sidecar")
+	}
+
+	if in.WorkloadSelector != nil {
+		errs = appendErrors(errs, Labels(in.WorkloadSelector.Labels).Validate())
+	}
+
+	for i, egress := range in.Egress {
+		errs = appendErrors(errs, validateSidecarEgressListener(i, egress))
+	}
+
+	portNumbers := make(map[uint32]bool)
+	for i, ingress := range in.Ingress {
+		if ingress.Port == nil {
+			errs = appendErrors(errs, fmt.Errorf("ingress[%d]: port is required", i))
+		} else {
+			errs = appendErrors(errs, validateServerPort(ingress.Port))
+			if portNumbers[ingress.Port.Number] {
+				errs = appendErrors(errs, fmt.Errorf("ingress[%d]: duplicate port number %d", i, ingress.Port.Number))
+			}
+			portNumbers[ingress.Port.Number] = true
+		}
+		if ingress.Bind != "" {
+			errs = appendErrors(errs, ValidateIPSubnet(ingress.Bind))
+		}
+		errs = appendErrors(errs, validateCaptureMode(ingress.CaptureMode, ingress.Bind))
+		errs = appendErrors(errs, validateSidecarDefaultEndpoint(i, ingress.DefaultEndpoint))
+	}
+
+	return
+}
+
+func validateSidecarEgressListener(index int, egress *networking.IstioEgressListener) (errs error) {
+	if egress.Port != nil {
+		errs = appendErrors(errs, validateServerPort(egress.Port))
+	}
+
+	if len(egress.Hosts) == 0 {
+		errs = appendErrors(errs, fmt.Errorf("egress[%d]: at least one host is required", index))
+	}
+	for _, host := range egress.Hosts {
+		errs = appendErrors(errs, validateSidecarEgressHost(index, host))
+	}
+
+	if egress.Bind != "" {
+		errs = appendErrors(errs, ValidateIPSubnet(egress.Bind))
+	}
+	errs = appendErrors(errs, validateCaptureMode(egress.CaptureMode, egress.Bind))
+
+	return
+}
+
+// validateSidecarEgressHost validates a "namespace/dnsName" egress host
+// entry. The namespace component may also be one of the special selectors
+// "*" (any namespace), "." (the Sidecar's own namespace), or "~" (no
+// namespace, i.e. Kubernetes Service entries only) — each only valid when
+// paired with the "*" dnsName.
+func validateSidecarEgressHost(index int, host string) error {
+	parts := strings.SplitN(host, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("egress[%d]: host %q must be of the form namespace/dnsName", index, host)
+	}
+	namespace, dnsName := parts[0], parts[1]
+
+	switch namespace {
+	case "*", ".", "~":
+		if dnsName != "*" {
+			return fmt.Errorf("egress[%d]: host %q: namespace %q may only be paired with dnsName \"*\"", index, host, namespace)
+		}
+		return nil
+	}
+	if !IsDNS1123Label(namespace) {
+		return fmt.Errorf("egress[%d]: host %q: namespace %q is not a valid label", index, host, namespace)
+	}
+	if dnsName == "*" {
+		return nil
+	}
+	return ValidateWildcardDomain(dnsName)
+}
+
+func validateCaptureMode(mode networking.CaptureMode, bind string) error {
+	switch mode {
+	case networking.CaptureMode_DEFAULT, networking.CaptureMode_IPTABLES:
+		return nil
+	case networking.CaptureMode_NONE:
+		if bind != "" && !loopbackAddresses[bind] {
+			return fmt.Errorf("captureMode NONE requires bind to be a loopback address, got %q", bind)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unrecognized captureMode %q", mode)
+	}
+}
+
+// validateSidecarDefaultEndpoint validates an ingress listener's
+// DefaultEndpoint, which must be either a unix domain socket path or an
+// IP:port pair bound to a loopback or wildcard address.
+func validateSidecarDefaultEndpoint(index int, endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("ingress[%d]: defaultEndpoint is required", index)
+	}
+	if strings.HasPrefix(endpoint, UnixAddressPrefix) {
+		return ValidateUnixAddress(strings.TrimPrefix(endpoint, UnixAddressPrefix))
+	}
+
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return fmt.Errorf("ingress[%d]: defaultEndpoint %q must be a unix:// path or host:port", index, endpoint)
+	}
+	if p, err := strconv.Atoi(port); err != nil || ValidatePort(p) != nil {
+		return fmt.Errorf("ingress[%d]: defaultEndpoint %q has an invalid port", index, endpoint)
+	}
+	if host != "127.0.0.1" && host != "0.0.0.0" && host != "::1" && host != "::" {
+		return fmt.Errorf("ingress[%d]: defaultEndpoint host must be 127.0.0.1, 0.0.0.0, ::1, or ::, got %q", index, host)
+	}
+	return nil
+}
+
 // ValidateServiceRole checks that ServiceRole is well-formed.
 func ValidateServiceRole(name, namespace string, msg proto.Message) error {
 	in, ok := msg.(*rbac.ServiceRole)
@@ -2064,11 +2952,9 @@ func validateHTTPFaultInjectionAbort(abort *networking.HTTPFaultInjection_Abort)
 
 	switch abort.ErrorType.(type) {
 	case *networking.HTTPFaultInjection_Abort_GrpcStatus:
-		// TODO: gRPC status validation
-		errs = multierror.Append(errs, errors.New("gRPC abort fault injection not supported yet"))
+		errs = appendErrors(errs, validateGrpcStatus(abort.GetGrpcStatus()))
 	case *networking.HTTPFaultInjection_Abort_Http2Error:
-		// TODO: HTTP2 error validation
-		errs = multierror.Append(errs, errors.New("HTTP/2 abort fault injection not supported yet"))
+		errs = appendErrors(errs, validateHTTP2Error(abort.GetHttp2Error()))
 	case *networking.HTTPFaultInjection_Abort_HttpStatus:
 		errs = appendErrors(errs, validateHTTPStatus(abort.GetHttpStatus()))
 	}
@@ -2076,6 +2962,77 @@ func validateHTTPFaultInjectionAbort(abort *networking.HTTPFaultInjection_Abort)
 	return
 }
 
+// grpcStatusCodes maps the canonical gRPC status code names (as used by
+// google.golang.org/grpc/codes) to their numeric values, 0 through 16.
+var grpcStatusCodes = map[string]int{
+	"OK":                  0,
+	"CANCELLED":           1,
+	"UNKNOWN":             2,
+	"INVALID_ARGUMENT":    3,
+	"DEADLINE_EXCEEDED":   4,
+	"NOT_FOUND":           5,
+	"ALREADY_EXISTS":      6,
+	"PERMISSION_DENIED":   7,
+	"RESOURCE_EXHAUSTED":  8,
+	"FAILED_PRECONDITION": 9,
+	"ABORTED":             10,
+	"OUT_OF_RANGE":        11,
+	"UNIMPLEMENTED":       12,
+	"INTERNAL":            13,
+	"UNAVAILABLE":         14,
+	"DATA_LOSS":           15,
+	"UNAUTHENTICATED":     16,
+}
+
+// validateGrpcStatus accepts a canonical gRPC status code name
+// (case-insensitive) or its numeric equivalent, 0-16.
+func validateGrpcStatus(status string) error {
+	if _, ok := grpcStatusCodes[strings.ToUpper(status)]; ok {
+		return nil
+	}
+	if code, err := strconv.Atoi(status); err == nil {
+		if code < 0 || code > 16 {
+			return fmt.Errorf("gRPC status code %d is not in range 0-16", code)
+		}
+		return nil
+	}
+	return fmt.Errorf("gRPC status %q is not a recognized status name or a numeric code in range 0-16", status)
+}
+
+// http2ErrorCodes maps the RFC 7540 HTTP/2 error code names to their
+// numeric values, 0x0 through 0xd.
+var http2ErrorCodes = map[string]int{
+	"NO_ERROR":            0x0,
+	"PROTOCOL_ERROR":      0x1,
+	"INTERNAL_ERROR":      0x2,
+	"FLOW_CONTROL_ERROR":  0x3,
+	"SETTINGS_TIMEOUT":    0x4,
+	"STREAM_CLOSED":       0x5,
+	"FRAME_SIZE_ERROR":    0x6,
+	"REFUSED_STREAM":      0x7,
+	"CANCEL":              0x8,
+	"COMPRESSION_ERROR":   0x9,
+	"CONNECT_ERROR":       0xa,
+	"ENHANCE_YOUR_CALM":   0xb,
+	"INADEQUATE_SECURITY": 0xc,
+	"HTTP_1_1_REQUIRED":   0xd,
+}
+
+// validateHTTP2Error accepts an RFC 7540 HTTP/2 error code name
+// (case-insensitive) or its numeric equivalent, 0x0-0xd.
+func validateHTTP2Error(errorType string) error {
+	if _, ok := http2ErrorCodes[strings.ToUpper(errorType)]; ok {
+		return nil
+	}
+	if code, err := strconv.ParseInt(errorType, 0, 64); err == nil {
+		if code < 0x0 || code > 0xd {
+			return fmt.Errorf("HTTP/2 error code %#x is not in range 0x0-0xd", code)
+		}
+		return nil
+	}
+	return fmt.Errorf("HTTP/2 error %q is not a recognized error name or a numeric code in range 0x0-0xd", errorType)
+}
+
 func validateHTTPStatus(status int32) error {
 	if status < 0 || status > 600 {
 		return fmt.Errorf("HTTP status %d is not in range 0-600", status)
@@ -2173,11 +3130,45 @@ func validateHTTPRetry(retries *networking.HTTPRetry) (errs error) {
 	return
 }
 
+// validateHTTPRedirect checks a VirtualService HTTP route's redirect
+// action. RedirectPort of 0 means "derive the port from the request",
+// matching the zero-value-is-default convention used elsewhere in this
+// package (e.g. ValidatePort's callers). StatusCode of 0 means "use the
+// implementation's default redirect code" (a 301/302/307/308 choice left
+// to the data plane).
 func validateHTTPRedirect(redirect *networking.HTTPRedirect) error {
-	if redirect != nil && redirect.Uri == "" && redirect.Authority == "" {
-		return errors.New("redirect must specify URI, authority, or both")
+	if redirect == nil {
+		return nil
 	}
-	return nil
+	var errs error
+
+	if redirect.Uri == "" && redirect.Authority == "" && redirect.Scheme == "" && redirect.RedirectPort == 0 && redirect.StatusCode == 0 {
+		errs = appendErrors(errs, errors.New("redirect must specify uri, authority, scheme, port, or status code"))
+	}
+
+	if redirect.Scheme != "" && redirect.Scheme != "http" && redirect.Scheme != "https" {
+		errs = appendErrors(errs, fmt.Errorf("redirect scheme must be \"http\" or \"https\", got %q", redirect.Scheme))
+	}
+
+	if redirect.RedirectPort != 0 {
+		if err := ValidatePort(int(redirect.RedirectPort)); err != nil {
+			errs = appendErrors(errs, multierror.Prefix(err, "redirect port invalid: "))
+		}
+		switch {
+		case redirect.Scheme == "https" && redirect.RedirectPort == 80:
+			errs = appendErrors(errs, fmt.Errorf("redirect scheme %q conflicts with hard-coded port 80", redirect.Scheme))
+		case redirect.Scheme == "http" && redirect.RedirectPort == 443:
+			errs = appendErrors(errs, fmt.Errorf("redirect scheme %q conflicts with hard-coded port 443", redirect.Scheme))
+		}
+	}
+
+	switch redirect.StatusCode {
+	case 0, 301, 302, 307, 308:
+	default:
+		errs = appendErrors(errs, fmt.Errorf("redirect status code must be one of 301, 302, 307, 308, got %d", redirect.StatusCode))
+	}
+
+	return errs
 }
 
 func validateHTTPRewrite(rewrite *networking.HTTPRewrite) error {
@@ -2206,20 +3197,76 @@ func ValidateServiceEntry(name, namespace string, config proto.Message) (errs er
 		}
 	}
 	for _, address := range serviceEntry.Addresses {
-		errs = appendErrors(errs, validateCIDR(address))
-	}
+		_, err := validateCIDRAny(address)
+		errs = appendErrors(errs, err)
+	}
+
+	// fieldErrs carries structured, path-aware errors for the per-endpoint
+	// checks below (e.g. "spec.endpoints[2].ports.http: ... not defined by
+	// the service entry") so istioctl validate and the admission webhook
+	// can point at the exact offending endpoint/port instead of a flat
+	// multierror string. The rest of this function still reports through
+	// the plain `errs error` return value; fieldErrs is folded into it at
+	// the end.
+	var fieldErrs field.ErrorList
+	endpointsPath := field.NewPath("spec").Child("endpoints")
+
+	fieldErrs = fieldErrs.Append(validateEndpointWeights(serviceEntry, endpointsPath)...)
+
+	// portRanges records the [lo, hi] bounds of any port declared with the
+	// "low-high" range syntax (Name holding the range, Number left unset),
+	// keyed by the port's Name, so the endpoint loops below can check a
+	// given endpoint port falls inside its declared range rather than just
+	// existing by name.
+	portRanges := make(map[string][2]uint32)
+
+	// portIsUDP records which protocol family (UDP-ish vs TCP-ish) has
+	// already claimed a given port number, so two ports may legitimately
+	// share a number as long as they're on different families (e.g. port 53
+	// TCP and port 53 UDP) - that's exactly the DNS-over-UDP/QUIC case this
+	// validator exists to unlock. Two ports of the *same* family sharing a
+	// number is still a conflict.
+	portIsUDP := make(map[uint32]bool)
+	hasUDPPort := false
 
-	servicePortNumbers := make(map[uint32]bool)
 	servicePorts := make(map[string]bool, len(serviceEntry.Ports))
 	for _, port := range serviceEntry.Ports {
 		if servicePorts[port.Name] {
 			errs = appendErrors(errs, fmt.Errorf("service entry port name %q already defined", port.Name))
 		}
 		servicePorts[port.Name] = true
-		if servicePortNumbers[port.Number] {
-			errs = appendErrors(errs, fmt.Errorf("service entry port %d already defined", port.Number))
+
+		isUDP := isUDPFamilyName(port.Protocol)
+		if isUDP {
+			hasUDPPort = true
+			if serviceEntry.Resolution == networking.ServiceEntry_DNS {
+				errs = appendErrors(errs, fmt.Errorf("port %q: protocol %s is not supported with resolution DNS: "+
+					"DNS fallback relies on TCP connection retries, which UDP/QUIC do not have", port.Name, port.Protocol))
+			}
+		}
+
+		if port.Number == 0 {
+			if lo, hi, err := ParsePortRange(port.Name); err == nil {
+				for name, bounds := range portRanges {
+					if lo <= bounds[1] && bounds[0] <= hi {
+						errs = appendErrors(errs, fmt.Errorf("port range %q overlaps with port range %q", port.Name, name))
+					}
+				}
+				portRanges[port.Name] = [2]uint32{lo, hi}
+				if serviceEntry.Resolution == networking.ServiceEntry_DNS {
+					errs = appendErrors(errs, fmt.Errorf("port range %q is not supported with resolution DNS: the resolver cannot demultiplex a single FQDN across a port range", port.Name))
+				}
+				continue
+			}
+		}
+
+		if existingIsUDP, ok := portIsUDP[port.Number]; ok {
+			if existingIsUDP == isUDP {
+				errs = appendErrors(errs, fmt.Errorf("service entry port %d already defined", port.Number))
+			}
+		} else {
+			portIsUDP[port.Number] = isUDP
 		}
-		servicePortNumbers[port.Number] = true
 	}
 
 	switch serviceEntry.Resolution {
@@ -2234,20 +3281,81 @@ func ValidateServiceEntry(name, namespace string, config proto.Message) (errs er
 		}
 
 		unixEndpoint := false
-		for _, endpoint := range serviceEntry.Endpoints {
+		var cidrEndpoints []*net.IPNet
+		var singleIPEndpoints []net.IP
+		for i, endpoint := range serviceEntry.Endpoints {
+			epPath := endpointsPath.Index(i)
 			addr := endpoint.GetAddress()
-			if strings.HasPrefix(addr, UnixAddressPrefix) {
+			switch {
+			case strings.HasPrefix(addr, UnixAddressPrefix):
 				unixEndpoint = true
 				errs = appendErrors(errs, ValidateUnixAddress(strings.TrimPrefix(addr, UnixAddressPrefix)))
 				if len(endpoint.Ports) != 0 {
 					errs = appendErrors(errs, fmt.Errorf("unix endpoint %s must not include ports", addr))
 				}
-			} else {
-				errs = appendErrors(errs, ValidateIPv4Address(addr))
+				if hasUDPPort {
+					errs = appendErrors(errs, fmt.Errorf("unix endpoint %s cannot be used with a UDP/QUIC service port: "+
+						"a Unix domain socket has no datagram framing to carry UDP traffic over", addr))
+				}
+			case addrtemplate.IsTemplate(addr):
+				// Templated addresses (e.g. "{{ GetPrivateIP }}") are resolved against the
+				// local host's interfaces at agent startup, not at config-validation time, so
+				// a template that doesn't currently resolve on this host is not an error here.
+				if _, err := addrtemplate.Parse(addr); err != nil {
+					errs = appendErrors(errs, err)
+				}
 
 				for name, port := range endpoint.Ports {
-					if !servicePorts[name] {
-						errs = appendErrors(errs, fmt.Errorf("endpoint port %v is not defined by the service entry", port))
+					if bounds, ranged := portRanges[name]; ranged {
+						if port < bounds[0] || port > bounds[1] {
+							fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port,
+								fmt.Sprintf("not in declared port range %d-%d", bounds[0], bounds[1])))
+						}
+					} else if !servicePorts[name] {
+						fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port, "not defined by the service entry"))
+					} else {
+						errs = appendErrors(errs, ValidatePort(int(port)))
+					}
+				}
+			case strings.Contains(addr, "/"):
+				// A CIDR block endpoint (e.g. "10.0.0.0/24") represents a whole
+				// subnet of backends - an allow-list for an external tier - rather
+				// than a single host, matching how Kubernetes' proxy util treats
+				// "0.0.0.0/0"/"::/0" as whole-address-space sentinels.
+				if _, ipnet, err := net.ParseCIDR(addr); err != nil {
+					errs = appendErrors(errs, fmt.Errorf("endpoint address %q is not a valid CIDR block: %v", addr, err))
+				} else {
+					cidrEndpoints = append(cidrEndpoints, ipnet)
+				}
+
+				for name, port := range endpoint.Ports {
+					if bounds, ranged := portRanges[name]; ranged {
+						if port < bounds[0] || port > bounds[1] {
+							fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port,
+								fmt.Sprintf("not in declared port range %d-%d", bounds[0], bounds[1])))
+						}
+					} else if !servicePorts[name] {
+						fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port, "not defined by the service entry"))
+					} else {
+						errs = appendErrors(errs, ValidatePort(int(port)))
+					}
+				}
+			default:
+				errs = appendErrors(errs, ValidateIPAddress(addr))
+				if ip := net.ParseIP(addr); ip != nil {
+					singleIPEndpoints = append(singleIPEndpoints, ip)
+				}
+
+				for name, port := range endpoint.Ports {
+					if bounds, ranged := portRanges[name]; ranged {
+						if port < bounds[0] || port > bounds[1] {
+							fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port,
+								fmt.Sprintf("not in declared port range %d-%d", bounds[0], bounds[1])))
+						}
+					} else if !servicePorts[name] {
+						fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port, "not defined by the service entry"))
+					} else {
+						errs = appendErrors(errs, ValidatePort(int(port)))
 					}
 				}
 			}
@@ -2257,6 +3365,8 @@ func ValidateServiceEntry(name, namespace string, config proto.Message) (errs er
 		if unixEndpoint && len(serviceEntry.Ports) != 1 {
 			errs = appendErrors(errs, errors.New("exactly 1 service port required for unix endpoints"))
 		}
+		errs = appendErrors(errs, validateNoCIDREndpointOverlap(cidrEndpoints, singleIPEndpoints))
+		errs = appendErrors(errs, validateConsistentEndpointFamily(cidrEndpoints, singleIPEndpoints))
 	case networking.ServiceEntry_DNS:
 		if len(serviceEntry.Endpoints) == 0 {
 			for _, host := range serviceEntry.Hosts {
@@ -2267,20 +3377,53 @@ func ValidateServiceEntry(name, namespace string, config proto.Message) (errs er
 			}
 		}
 
-		for _, endpoint := range serviceEntry.Endpoints {
-			errs = appendErrors(errs,
-				ValidateFQDN(endpoint.Address),
-				Labels(endpoint.Labels).Validate())
+		for i, endpoint := range serviceEntry.Endpoints {
+			epPath := endpointsPath.Index(i)
+			addr := endpoint.GetAddress()
+			if strings.HasPrefix(addr, UnixAddressPrefix) {
+				errs = appendErrors(errs, fmt.Errorf("unix endpoint %s is not allowed with discovery mode DNS; unix endpoints require discovery mode STATIC", addr))
+			} else if ValidateFQDN(addr) != nil && ValidateIPAddress(addr) != nil {
+				errs = appendErrors(errs, fmt.Errorf("endpoint address %q for discovery mode DNS must be a hostname or IP address", addr))
+			}
+			errs = appendErrors(errs, Labels(endpoint.Labels).Validate())
 
 			for name, port := range endpoint.Ports {
+				if bounds, ranged := portRanges[name]; ranged {
+					if port < bounds[0] || port > bounds[1] {
+						fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port,
+							fmt.Sprintf("not in declared port range %d-%d", bounds[0], bounds[1])))
+					}
+					errs = appendErrors(errs, validatePortName(name))
+					continue
+				}
 				if !servicePorts[name] {
-					errs = appendErrors(errs, fmt.Errorf("endpoint port %v is not defined by the service entry", port))
+					fieldErrs = fieldErrs.Append(field.Invalid(epPath.Child("ports").Key(name), port, "not defined by the service entry"))
 				}
 				errs = appendErrors(errs,
 					validatePortName(name),
 					ValidatePort(int(port)))
 			}
 		}
+	case networking.ServiceEntry_DNS_SRV:
+		if len(serviceEntry.Endpoints) != 0 {
+			errs = appendErrors(errs, fmt.Errorf("endpoints must not be provided for discovery mode DNS_SRV; "+
+				"endpoints are discovered via periodic SRV queries against the service hosts"))
+		}
+		if len(serviceEntry.Hosts) == 0 {
+			errs = appendErrors(errs, fmt.Errorf("at least one host is required for discovery mode DNS_SRV"))
+		}
+		for _, host := range serviceEntry.Hosts {
+			if strings.Contains(host, "*") {
+				errs = appendErrors(errs, fmt.Errorf("host %q must be a plain FQDN for discovery mode DNS_SRV, wildcards are not allowed", host))
+			} else if err := ValidateFQDN(host); err != nil {
+				errs = appendErrors(errs, err)
+			}
+		}
+		if len(serviceEntry.Ports) != 1 {
+			errs = appendErrors(errs, fmt.Errorf("exactly one port is required for discovery mode DNS_SRV"))
+		} else if port := serviceEntry.Ports[0]; !strings.HasPrefix(port.Name, "_") {
+			errs = appendErrors(errs, fmt.Errorf("port name %q must be the _proto label the SRV records are queried under (e.g. _tcp)", port.Name))
+		}
 	default:
 		errs = appendErrors(errs, fmt.Errorf("unsupported resolution type %s",
 			networking.ServiceEntry_Resolution_name[int32(serviceEntry.Resolution)]))
@@ -2289,27 +3432,59 @@ func ValidateServiceEntry(name, namespace string, config proto.Message) (errs er
 	for _, port := range serviceEntry.Ports {
 		errs = appendErrors(errs,
 			validatePortName(port.Name),
-			validateProtocol(port.Protocol),
-			ValidatePort(int(port.Number)))
+			validateProtocol(port.Protocol))
+		if _, ranged := portRanges[port.Name]; !ranged {
+			errs = appendErrors(errs, ValidatePort(int(port.Number)))
+		}
 	}
 
+	errs = appendErrors(errs, fieldErrs.ToAggregate())
+
 	return
 }
 
+// validatePortName checks that name is either a valid DNS1123 label, or a
+// "low-high" port range (the syntax a ServiceEntry port may use in place of
+// a single Number - see ParsePortRange).
 func validatePortName(name string) error {
-	if !IsDNS1123Label(name) {
-		return fmt.Errorf("invalid port name: %s", name)
+	if IsDNS1123Label(name) {
+		return nil
 	}
-	return nil
+	if _, _, err := ParsePortRange(name); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid port name: %s", name)
+}
+
+// ValidatePortName is the exported form of validatePortName, for callers
+// outside this package (e.g. pkg/config/validation's re-exported surface)
+// that need to validate a ServiceEntry port name without depending on
+// ValidateServiceEntry's full object validation.
+func ValidatePortName(name string) error {
+	return validatePortName(name)
 }
 
 func validateProtocol(protocol string) error {
+	// Check the RegisterProtocol registry before ParseProtocol, so a name
+	// ParseProtocol doesn't know about yet (QUIC self-registers this way;
+	// see protocol.go's init) is still accepted.
+	if v, ok := lookupCustomProtocol(protocol); ok {
+		if v != nil {
+			return v(protocol)
+		}
+		return nil
+	}
 	if ParseProtocol(protocol) == ProtocolUnsupported {
-		return fmt.Errorf("unsupported protocol: %s", protocol)
+		return fmt.Errorf("invalid protocol %q, supported protocols are HTTP, HTTP2, GRPC, MONGO, REDIS, TCP, UDP, QUIC", protocol)
 	}
 	return nil
 }
 
+// ValidateProtocol is the exported form of validateProtocol.
+func ValidateProtocol(protocol string) error {
+	return validateProtocol(protocol)
+}
+
 // wrapper around multierror.Append that enforces the invariant that if all input errors are nil, the output
 // error is nil (allowing validation without branching).
 func appendErrors(err error, errs ...error) error {
@@ -2328,19 +3503,127 @@ func appendErrors(err error, errs ...error) error {
 	return err
 }
 
+// AppendErrors is the exported form of appendErrors, for callers outside
+// this package that want to accumulate a Validate*-style error the same way
+// this package's own Validate* functions do.
+func AppendErrors(err error, errs ...error) error {
+	return appendErrors(err, errs...)
+}
+
 // ValidateNetworkEndpointAddress checks the Address field of a NetworkEndpoint. If the family is TCP, it checks the
-// address is a valid IP address. If the family is Unix, it checks the address is a valid socket file path.
+// address is a valid IP address (v4 or v6, but rejecting an IPv4-mapped-IPv6
+// literal such as "::ffff:1.2.3.4" unless SetAllowIPv4MappedIPv6 has enabled
+// it - that form is almost always a configuration mistake rather than an
+// intentional dual-stack address). If the family is Unix, it checks the
+// address is a valid socket file path.
+//
+// This is a thin wrapper over validateNetworkEndpointAddress that collapses
+// the field.ErrorList into a plain error for existing callers.
 func ValidateNetworkEndpointAddress(n *NetworkEndpoint) error {
+	return validateNetworkEndpointAddress(field.NewPath("address"), n).ToAggregate()
+}
+
+var (
+	allowIPv4MappedIPv6Mu sync.RWMutex
+	// allowIPv4MappedIPv6 defaults to false because an IPv4-mapped-IPv6
+	// literal almost always indicates a v4 address that was accidentally
+	// formatted as v6 rather than a deliberate dual-stack endpoint. Guarded
+	// by allowIPv4MappedIPv6Mu the same way protocol.go guards
+	// customProtocols, since ValidateNetworkEndpointAddress can be called
+	// concurrently while this process-wide toggle is (re)configured.
+	allowIPv4MappedIPv6 bool
+)
+
+// SetAllowIPv4MappedIPv6 controls whether ValidateNetworkEndpointAddress
+// accepts IPv4-mapped-IPv6 literals (e.g. "::ffff:1.2.3.4") for
+// AddressFamilyTCP endpoints.
+func SetAllowIPv4MappedIPv6(allow bool) {
+	allowIPv4MappedIPv6Mu.Lock()
+	defer allowIPv4MappedIPv6Mu.Unlock()
+	allowIPv4MappedIPv6 = allow
+}
+
+func allowingIPv4MappedIPv6() bool {
+	allowIPv4MappedIPv6Mu.RLock()
+	defer allowIPv4MappedIPv6Mu.RUnlock()
+	return allowIPv4MappedIPv6
+}
+
+func validateNetworkEndpointAddress(fldPath *field.Path, n *NetworkEndpoint) (errs field.ErrorList) {
 	switch n.Family {
 	case AddressFamilyTCP:
-		ipAddr := net.ParseIP(n.Address)
-		if ipAddr == nil {
-			return errors.New("invalid IP address " + n.Address)
+		ip := net.ParseIP(n.Address)
+		if ip == nil {
+			errs = errs.Append(field.Invalid(fldPath, n.Address, "not a valid IP address"))
+			break
+		}
+		if !allowingIPv4MappedIPv6() && isIPv4MappedIPv6Literal(n.Address, ip) {
+			errs = errs.Append(field.Invalid(fldPath, n.Address,
+				"is an IPv4-mapped-IPv6 literal; use the plain IPv4 form, or call SetAllowIPv4MappedIPv6(true) if this is intentional"))
 		}
 	case AddressFamilyUnix:
-		return ValidateUnixAddress(n.Address)
+		errs = errs.WrapError(fldPath, n.Address, ValidateUnixAddress(n.Address))
 	default:
 		panic(fmt.Sprintf("unhandled Family %v", n.Family))
 	}
-	return nil
+	return errs
+}
+
+// ResolveEndpointAddress resolves a ServiceEntry STATIC endpoint's Address
+// against the local host if it's an addrtemplate expression (see
+// addrtemplate.IsTemplate), otherwise it returns addr unchanged.
+// ValidateServiceEntry only parses a templated address, deliberately not
+// resolving it - resolution depends on the agent's host's network
+// interfaces, not the validating host's - so whatever materializes a
+// ServiceEntry's endpoints into the data plane (e.g. the agent's endpoint
+// bootstrap) should call this once per templated endpoint address instead of
+// hand-rolling addrtemplate.IsTemplate/Parse/Resolve itself.
+func ResolveEndpointAddress(ctx context.Context, addr string) (string, error) {
+	if !addrtemplate.IsTemplate(addr) {
+		return addr, nil
+	}
+	tmpl, err := addrtemplate.Parse(addr)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Resolve(ctx)
+}
+
+// isIPv4MappedIPv6Literal reports whether raw was written in IPv6 notation
+// (i.e. it contains a colon) but parses to an address that's also
+// expressible as plain IPv4 - the telltale sign of an "::ffff:a.b.c.d"
+// IPv4-mapped-IPv6 literal rather than a genuine IPv6 address.
+func isIPv4MappedIPv6Literal(raw string, ip net.IP) bool {
+	return strings.Contains(raw, ":") && ip.To4() != nil
+}
+
+// ipFamily returns "ipv4" or "ipv6" for ip, the way this package reports an
+// endpoint's address family in error messages.
+func ipFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// SplitHostPort parses a "host:port" or bracketed "[host]:port" address -
+// the form used by Gateway server Bind/Port.TargetPort fields and other
+// places an address and port travel together in a single string - and
+// validates both the host (a plain IPv4 or IPv6 literal) and the port.
+func SplitHostPort(hostport string) (host string, port int, err error) {
+	host, portStr, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return "", 0, fmt.Errorf("%q is not a valid host:port address: %v", hostport, err)
+	}
+	if err := ValidateIPAddress(host); err != nil {
+		return "", 0, fmt.Errorf("%q is not a valid host:port address: %v", hostport, err)
+	}
+	portNum, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("%q is not a valid host:port address: port %q is not a number", hostport, portStr)
+	}
+	if err := ValidatePort(portNum); err != nil {
+		return "", 0, err
+	}
+	return host, portNum, nil
 }
@@ -0,0 +1,89 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+
+	"istio.io/istio/pilot/pkg/model/field"
+)
+
+func TestValidateEndpointWeights(t *testing.T) {
+	cases := []struct {
+		name      string
+		endpoints []*networking.ServiceEntry_Endpoint
+		wantErr   bool
+	}{
+		{
+			name: "all weighted",
+			endpoints: []*networking.ServiceEntry_Endpoint{
+				{Address: "1.2.3.4", Weight: 10},
+				{Address: "1.2.3.5", Weight: 20},
+			},
+			wantErr: false,
+		},
+		{
+			name: "none weighted",
+			endpoints: []*networking.ServiceEntry_Endpoint{
+				{Address: "1.2.3.4"},
+				{Address: "1.2.3.5"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "mixed weighted and unweighted is rejected",
+			endpoints: []*networking.ServiceEntry_Endpoint{
+				{Address: "1.2.3.4", Weight: 10},
+				{Address: "1.2.3.5"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "weight above max is rejected",
+			endpoints: []*networking.ServiceEntry_Endpoint{
+				{Address: "1.2.3.4", Weight: maxEndpointWeight + 1},
+			},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			se := &networking.ServiceEntry{
+				Resolution: networking.ServiceEntry_STATIC,
+				Endpoints:  c.endpoints,
+			}
+			errs := validateEndpointWeights(se, field.NewPath("endpoints"))
+			if (len(errs) > 0) != c.wantErr {
+				t.Errorf("validateEndpointWeights() errs = %v, wantErr %v", errs, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEndpointWeightsIgnoredUnderNoneResolution(t *testing.T) {
+	se := &networking.ServiceEntry{
+		Resolution: networking.ServiceEntry_NONE,
+		Endpoints: []*networking.ServiceEntry_Endpoint{
+			{Address: "1.2.3.4", Weight: 10},
+			{Address: "1.2.3.5"},
+		},
+	}
+	errs := validateEndpointWeights(se, field.NewPath("endpoints"))
+	if len(errs) > 0 {
+		t.Errorf("validateEndpointWeights() under NONE resolution = %v, want no errors (caller already flags Endpoints under NONE)", errs)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	routing "istio.io/api/routing/v1alpha1"
+)
+
+func TestValidateIPAddressDualStack(t *testing.T) {
+	cases := []struct {
+		name    string
+		addr    string
+		wantErr bool
+	}{
+		{"loopback v6", "::1", false},
+		{"link-local v6", "fe80::1", false},
+		{"ipv4-mapped ipv6", "::ffff:1.2.3.4", false},
+		{"loopback v4", "127.0.0.1", false},
+		{"garbage", "not-an-ip", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateIPAddress(c.addr)
+			if (err != nil) != c.wantErr {
+				t.Errorf("ValidateIPAddress(%q) = %v, wantErr %v", c.addr, err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateIPSubnetDualStack(t *testing.T) {
+	cases := []struct {
+		name    string
+		subnet  string
+		wantErr bool
+	}{
+		{"v6 link-local block", "fe80::/10", false},
+		{"v6 loopback address", "::1", false},
+		{"v4 cidr", "10.0.0.0/24", false},
+		{"v4 address", "10.0.0.1", false},
+		{"garbage cidr", "fe80::/xx", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateIPSubnet(c.subnet)
+			if (err != nil) != c.wantErr {
+				t.Errorf("ValidateIPSubnet(%q) = %v, wantErr %v", c.subnet, err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateIPv4AddressStillRejectsIPv6(t *testing.T) {
+	if err := ValidateIPv4Address("::1"); err == nil {
+		t.Error("ValidateIPv4Address(::1) = nil, want error: IPv4-only helpers must stay IPv4-only")
+	}
+	if err := ValidateIPv4Address("10.0.0.1"); err != nil {
+		t.Errorf("ValidateIPv4Address(10.0.0.1) = %v, want nil", err)
+	}
+}
+
+func TestValidateL4MatchAttributesMixedFamilies(t *testing.T) {
+	ma := &routing.L4MatchAttributes{
+		SourceSubnet:      []string{"10.0.0.0/24", "fe80::/10"},
+		DestinationSubnet: []string{"::1", "192.168.1.1"},
+	}
+	if err := ValidateL4MatchAttributes(ma); err != nil {
+		t.Errorf("ValidateL4MatchAttributes with mixed v4/v6 subnets = %v, want nil", err)
+	}
+}
+
+func TestValidateL4MatchAttributesRejectsInvalidSubnet(t *testing.T) {
+	ma := &routing.L4MatchAttributes{
+		SourceSubnet: []string{"not-a-subnet"},
+	}
+	if err := ValidateL4MatchAttributes(ma); err == nil {
+		t.Error("ValidateL4MatchAttributes with an invalid subnet = nil, want error")
+	}
+}
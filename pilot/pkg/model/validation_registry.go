@@ -0,0 +1,173 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// GroupVersionKind identifies a config schema the same way a Kubernetes CRD
+// does, so a ValidatorRegistry entry can be looked up without callers
+// needing to know which Go type backs it.
+type GroupVersionKind struct {
+	Group   string
+	Version string
+	Kind    string
+}
+
+func (g GroupVersionKind) String() string {
+	return fmt.Sprintf("%s/%s, Kind=%s", g.Group, g.Version, g.Kind)
+}
+
+// ValidateFn validates a single named config object, in the style of the
+// Validate* functions elsewhere in this package.
+type ValidateFn func(name, namespace string, msg proto.Message) error
+
+// Schema carries the metadata a ValidatorRegistry needs about a config kind
+// beyond "how do I validate one": which fields are on their way out, which
+// API versions it's valid in, and whether it's gated behind a feature flag.
+type Schema struct {
+	GVK GroupVersionKind
+
+	// DeprecatedFields lists field names (dotted paths, e.g.
+	// "spec.http.redirect.port") that are still accepted but that
+	// ValidateFn implementations may warn about; it's metadata for
+	// documentation/export, not enforced by Validate itself.
+	DeprecatedFields []string
+
+	// MinAPIVersion and MaxAPIVersion bound the mesh-wide API version this
+	// schema is valid for, e.g. "1.5" / "". An empty MaxAPIVersion means no
+	// upper bound.
+	MinAPIVersion string
+	MaxAPIVersion string
+
+	// FeatureFlag, if set, names the feature gate that must be enabled for
+	// Validate to accept objects of this kind. See IsFeatureEnabled.
+	FeatureFlag string
+}
+
+// IsFeatureEnabled reports whether the named feature flag is enabled.
+// Downstream binaries that wire up real feature gating should replace this
+// var with their own lookup; it defaults to "everything is enabled" so
+// schemas with no FeatureFlag behave exactly as before the registry existed.
+var IsFeatureEnabled = func(flag string) bool { return true }
+
+type registryEntry struct {
+	schema   Schema
+	validate ValidateFn
+}
+
+// ValidatorRegistry is a (group, version, kind)-keyed collection of
+// Validate* functions, replacing ad hoc dispatch on the Go type of a config
+// object (e.g. a big type switch in a ConfigStore). It plays the same role
+// here that a builder+registry plays in Consul's config entry validation:
+// callers register a Schema once, and every subsequent Validate call for
+// that kind goes through the same metadata-aware path.
+type ValidatorRegistry struct {
+	mu      sync.RWMutex
+	entries map[GroupVersionKind]registryEntry
+}
+
+// NewValidatorRegistry returns an empty ValidatorRegistry.
+func NewValidatorRegistry() *ValidatorRegistry {
+	return &ValidatorRegistry{entries: make(map[GroupVersionKind]registryEntry)}
+}
+
+// Register adds schema to r, validated by fn. It panics if schema.GVK is
+// already registered, since that indicates two packages both claiming to
+// own the same config kind rather than a condition callers can recover
+// from.
+func (r *ValidatorRegistry) Register(schema Schema, fn ValidateFn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[schema.GVK]; ok {
+		panic(fmt.Sprintf("model: duplicate ValidatorRegistry registration for %s", schema.GVK))
+	}
+	r.entries[schema.GVK] = registryEntry{schema: schema, validate: fn}
+}
+
+// Validate looks up the ValidateFn registered for gvk and runs it against
+// msg. It returns an error if no schema is registered for gvk, or if the
+// schema's FeatureFlag is set but not enabled.
+func (r *ValidatorRegistry) Validate(gvk GroupVersionKind, name, namespace string, msg proto.Message) error {
+	r.mu.RLock()
+	entry, ok := r.entries[gvk]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no validator registered for %s", gvk)
+	}
+	if entry.schema.FeatureFlag != "" && !IsFeatureEnabled(entry.schema.FeatureFlag) {
+		return fmt.Errorf("%s is gated behind disabled feature %q", gvk, entry.schema.FeatureFlag)
+	}
+	return entry.validate(name, namespace, msg)
+}
+
+// Schema returns the Schema registered for gvk, and whether one was found.
+func (r *ValidatorRegistry) Schema(gvk GroupVersionKind) (Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[gvk]
+	return entry.schema, ok
+}
+
+// Schemas returns every registered Schema, in no particular order.
+func (r *ValidatorRegistry) Schemas() []Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Schema, 0, len(r.entries))
+	for _, entry := range r.entries {
+		out = append(out, entry.schema)
+	}
+	return out
+}
+
+// DefaultValidators is the ValidatorRegistry populated with every Validate*
+// function in this package that has a stable (group, version, kind). Code
+// that dispatches validation by config kind (Galley, the webhook, istioctl)
+// should go through DefaultValidators.Validate rather than switching on Go
+// types directly.
+var DefaultValidators = NewValidatorRegistry()
+
+func init() {
+	register := DefaultValidators.Register
+	const networkingGroup = "networking.istio.io"
+	const securityGroup = "security.istio.io"
+
+	register(Schema{GVK: GroupVersionKind{Group: networkingGroup, Version: "v1alpha3", Kind: "VirtualService"}}, ValidateVirtualService)
+	register(Schema{GVK: GroupVersionKind{Group: networkingGroup, Version: "v1alpha3", Kind: "Gateway"}}, ValidateGateway)
+	register(Schema{GVK: GroupVersionKind{Group: networkingGroup, Version: "v1alpha3", Kind: "DestinationRule"}}, ValidateDestinationRule)
+	register(Schema{GVK: GroupVersionKind{Group: networkingGroup, Version: "v1alpha3", Kind: "ServiceEntry"}}, ValidateServiceEntry)
+	register(Schema{GVK: GroupVersionKind{Group: networkingGroup, Version: "v1alpha3", Kind: "Sidecar"}}, ValidateSidecar)
+	register(Schema{GVK: GroupVersionKind{Group: networkingGroup, Version: "v1alpha3", Kind: "EnvoyFilter"}}, ValidateEnvoyFilter)
+	register(Schema{
+		GVK:           GroupVersionKind{Group: "extensions.istio.io", Version: "v1alpha1", Kind: "WasmPlugin"},
+		MinAPIVersion: "1.10",
+	}, ValidateWasmPlugin)
+	register(Schema{GVK: GroupVersionKind{Group: securityGroup, Version: "v1beta1", Kind: "PeerAuthentication"}}, ValidatePeerAuthentication)
+	register(Schema{GVK: GroupVersionKind{Group: securityGroup, Version: "v1beta1", Kind: "RequestAuthentication"}}, ValidateRequestAuthentication)
+	register(Schema{GVK: GroupVersionKind{Group: securityGroup, Version: "v1beta1", Kind: "AuthorizationPolicy"}}, ValidateAuthorizationPolicy)
+
+	// EgressRule predates the networking.istio.io group and is on its way
+	// out in favor of ServiceEntry; keep it registered so existing configs
+	// still validate, for API versions up to 1.1. Nothing marks it as
+	// deprecated in the schema export.
+	register(Schema{
+		GVK:           GroupVersionKind{Group: "config.istio.io", Version: "v1alpha1", Kind: "EgressRule"},
+		MaxAPIVersion: "1.1",
+	}, ValidateEgressRule)
+}
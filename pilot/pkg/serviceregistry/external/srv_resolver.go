@@ -0,0 +1,210 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	networking "istio.io/api/networking/v1alpha3"
+
+	"istio.io/istio/pilot/pkg/model"
+)
+
+// Mirrors model.discoveryRefreshDelayMin/Max: the refresh cadence for a
+// DNS_SRV ServiceEntry is clamped to this range regardless of the TTL
+// reported by the nameserver.
+const (
+	minRefreshDelay = time.Second
+	maxRefreshDelay = time.Minute * 10
+)
+
+// SRVResolver resolves the DNS_SRV ServiceEntry endpoints for a single
+// host:port pair. It is implemented in terms of github.com/miekg/dns
+// (rather than net.LookupSRV) so that per-record TTLs, a custom resolver,
+// and EDNS0 client-subnet can be honored.
+type SRVResolver interface {
+	// Resolve issues a `_service._proto.host` SRV query and returns the
+	// resolved endpoints together with the TTL the caller should wait
+	// before refreshing.
+	Resolve(ctx context.Context, service, proto, host string) (Targets, time.Duration, error)
+}
+
+// Targets is the set of endpoints a SRV query resolved to, each with the
+// address and port taken from the SRV target and A/AAAA glue records.
+type Targets []Target
+
+// Target is a single resolved SRV target.
+type Target struct {
+	Host     string
+	Address  string
+	Port     uint16
+	Priority uint16
+	Weight   uint16
+}
+
+// DefaultResolver is the production SRVResolver, backed by a
+// github.com/miekg/dns client. Tests (and operators who want to point at a
+// non-default nameserver per ServiceEntry) can substitute their own
+// SRVResolver instead.
+type DefaultResolver struct {
+	// Client issues the actual DNS queries. Defaults to a plain UDP client
+	// against the system-configured resolver when nil.
+	Client *dns.Client
+	// Nameserver, if set, overrides the system resolver (host:port form).
+	// When empty, the first nameserver in /etc/resolv.conf is used, or
+	// 127.0.0.1:53 if that file cannot be read.
+	Nameserver string
+}
+
+// Resolve implements SRVResolver.
+func (r *DefaultResolver) Resolve(ctx context.Context, service, proto, host string) (Targets, time.Duration, error) {
+	client := r.Client
+	if client == nil {
+		client = &dns.Client{}
+	}
+
+	qname := dns.Fqdn(fmt.Sprintf("_%s._%s.%s", service, proto, host))
+	msg := new(dns.Msg)
+	msg.SetQuestion(qname, dns.TypeSRV)
+	msg.SetEdns0(4096, false)
+
+	nameserver := r.Nameserver
+	if nameserver == "" {
+		nameserver = "127.0.0.1:53"
+		if conf, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil && len(conf.Servers) > 0 {
+			nameserver = net.JoinHostPort(conf.Servers[0], conf.Port)
+		}
+	}
+
+	resp, _, err := client.ExchangeContext(ctx, msg, nameserver)
+	if err != nil {
+		return nil, 0, fmt.Errorf("SRV query for %s failed: %v", qname, err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, 0, fmt.Errorf("SRV query for %s returned rcode %s", qname, dns.RcodeToString[resp.Rcode])
+	}
+
+	glue := map[string]string{}
+	for _, rr := range resp.Extra {
+		switch a := rr.(type) {
+		case *dns.A:
+			glue[a.Hdr.Name] = a.A.String()
+		case *dns.AAAA:
+			glue[a.Hdr.Name] = a.AAAA.String()
+		}
+	}
+
+	var targets Targets
+	minTTL := maxRefreshDelay
+	for _, rr := range resp.Answer {
+		srv, ok := rr.(*dns.SRV)
+		if !ok {
+			continue
+		}
+		ttl := time.Duration(srv.Hdr.Ttl) * time.Second
+		if ttl < minTTL {
+			minTTL = ttl
+		}
+		addr := srv.Target
+		if resolved, ok := glue[srv.Target]; ok {
+			addr = resolved
+		}
+		targets = append(targets, Target{
+			Host:     strings.TrimSuffix(srv.Target, "."),
+			Address:  strings.TrimSuffix(addr, "."),
+			Port:     srv.Port,
+			Priority: srv.Priority,
+			Weight:   srv.Weight,
+		})
+	}
+
+	return targets, clampRefreshDelay(minTTL), nil
+}
+
+// clampRefreshDelay enforces min(TTL, maxRefreshDelay), bounded below by
+// minRefreshDelay, matching the discoveryRefreshDelayMin/Max semantics used
+// elsewhere in Pilot for periodic refresh.
+func clampRefreshDelay(ttl time.Duration) time.Duration {
+	if ttl > maxRefreshDelay {
+		return maxRefreshDelay
+	}
+	if ttl < minRefreshDelay {
+		return minRefreshDelay
+	}
+	return ttl
+}
+
+// ResolveServiceEntry resolves every endpoint for a DNS_SRV ServiceEntry and
+// materializes one model.IstioEndpoint per SRV target, using the target's
+// resolved address/port. The ServiceEntry's single port name supplies the
+// `_proto` label, and each host's first DNS label the `_service` label, of
+// the SRV query issued against that host.
+func ResolveServiceEntry(ctx context.Context, resolver SRVResolver, se *networking.ServiceEntry) ([]*model.IstioEndpoint, time.Duration, error) {
+	if len(se.Ports) != 1 {
+		return nil, 0, fmt.Errorf("DNS_SRV service entries must declare exactly one port")
+	}
+	proto, err := splitSRVPortName(se.Ports[0].Name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var endpoints []*model.IstioEndpoint
+	refresh := maxRefreshDelay
+	for _, host := range se.Hosts {
+		service := serviceLabel(host)
+		targets, delay, err := resolver.Resolve(ctx, service, proto, host)
+		if err != nil {
+			return nil, 0, err
+		}
+		if delay < refresh {
+			refresh = delay
+		}
+		for _, t := range targets {
+			endpoints = append(endpoints, &model.IstioEndpoint{
+				Address:      t.Address,
+				EndpointPort: uint32(t.Port),
+			})
+		}
+	}
+
+	return endpoints, refresh, nil
+}
+
+// splitSRVPortName extracts the proto label from a ServiceEntry port name
+// of the form "_proto" (e.g. "_tcp" -> "tcp"), the second half of the
+// `_service._proto.host` qname an SRV query uses.
+func splitSRVPortName(name string) (proto string, err error) {
+	if len(name) < 2 || name[0] != '_' {
+		return "", fmt.Errorf("port name %q is not a valid _proto label", name)
+	}
+	return name[1:], nil
+}
+
+// serviceLabel derives the "service" component of `_service._proto.host`
+// from host's first DNS label, by convention (e.g. "ldap.example.com" ->
+// "ldap"). Queried per host rather than once per ServiceEntry, since a
+// DNS_SRV ServiceEntry may list hosts for more than one underlying service.
+func serviceLabel(host string) string {
+	if i := strings.IndexByte(host, '.'); i >= 0 {
+		return host[:i]
+	}
+	return host
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	networking "istio.io/api/networking/v1alpha3"
+)
+
+// fakeResolver is a hand-written SRVResolver a test can point at canned
+// answers, standing in for the dns.Client the DefaultResolver wraps.
+type fakeResolver struct {
+	targets Targets
+	delay   time.Duration
+	err     error
+
+	lastService, lastProto, lastHost string
+}
+
+func (f *fakeResolver) Resolve(_ context.Context, service, proto, host string) (Targets, time.Duration, error) {
+	f.lastService, f.lastProto, f.lastHost = service, proto, host
+	return f.targets, f.delay, f.err
+}
+
+func TestResolveServiceEntryUsesHostDerivedServiceLabel(t *testing.T) {
+	fr := &fakeResolver{
+		targets: Targets{{Host: "t1.example.com", Address: "10.0.0.1", Port: 5432}},
+		delay:   30 * time.Second,
+	}
+	se := &networking.ServiceEntry{
+		Hosts: []string{"ldap.example.com"},
+		Ports: []*networking.Port{{Name: "_tcp"}},
+	}
+
+	endpoints, refresh, err := ResolveServiceEntry(context.Background(), fr, se)
+	if err != nil {
+		t.Fatalf("ResolveServiceEntry() error = %v", err)
+	}
+	if fr.lastService != "ldap" {
+		t.Errorf("resolver queried service %q, want %q (derived from host's first label)", fr.lastService, "ldap")
+	}
+	if fr.lastProto != "tcp" {
+		t.Errorf("resolver queried proto %q, want %q", fr.lastProto, "tcp")
+	}
+	if fr.lastHost != "ldap.example.com" {
+		t.Errorf("resolver queried host %q, want %q", fr.lastHost, "ldap.example.com")
+	}
+	if len(endpoints) != 1 || endpoints[0].Address != "10.0.0.1" || endpoints[0].EndpointPort != 5432 {
+		t.Errorf("endpoints = %+v, want one endpoint at 10.0.0.1:5432", endpoints)
+	}
+	if refresh != 30*time.Second {
+		t.Errorf("refresh = %v, want %v", refresh, 30*time.Second)
+	}
+}
+
+func TestResolveServiceEntryRejectsMultiplePorts(t *testing.T) {
+	se := &networking.ServiceEntry{
+		Hosts: []string{"ldap.example.com"},
+		Ports: []*networking.Port{{Name: "_tcp"}, {Name: "_udp"}},
+	}
+	if _, _, err := ResolveServiceEntry(context.Background(), &fakeResolver{}, se); err == nil {
+		t.Error("ResolveServiceEntry with two ports = nil error, want error")
+	}
+}
+
+func TestResolveServiceEntryTakesMinimumRefreshAcrossHosts(t *testing.T) {
+	calls := 0
+	var delays = []time.Duration{45 * time.Second, 10 * time.Second}
+	resolver := resolverFunc(func(_ context.Context, service, proto, host string) (Targets, time.Duration, error) {
+		d := delays[calls]
+		calls++
+		return nil, d, nil
+	})
+	se := &networking.ServiceEntry{
+		Hosts: []string{"a.example.com", "b.example.com"},
+		Ports: []*networking.Port{{Name: "_tcp"}},
+	}
+
+	_, refresh, err := ResolveServiceEntry(context.Background(), resolver, se)
+	if err != nil {
+		t.Fatalf("ResolveServiceEntry() error = %v", err)
+	}
+	if refresh != 10*time.Second {
+		t.Errorf("refresh = %v, want the smaller of the two hosts' delays (%v)", refresh, 10*time.Second)
+	}
+}
+
+// resolverFunc adapts a plain function to SRVResolver, the way http.HandlerFunc
+// adapts a function to http.Handler.
+type resolverFunc func(ctx context.Context, service, proto, host string) (Targets, time.Duration, error)
+
+func (f resolverFunc) Resolve(ctx context.Context, service, proto, host string) (Targets, time.Duration, error) {
+	return f(ctx, service, proto, host)
+}
+
+func TestSplitSRVPortName(t *testing.T) {
+	cases := []struct {
+		name      string
+		portName  string
+		wantProto string
+		wantErr   bool
+	}{
+		{"tcp", "_tcp", "tcp", false},
+		{"udp", "_udp", "udp", false},
+		{"missing underscore", "tcp", "", true},
+		{"empty", "", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			proto, err := splitSRVPortName(c.portName)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("splitSRVPortName(%q) error = %v, wantErr %v", c.portName, err, c.wantErr)
+			}
+			if err == nil && proto != c.wantProto {
+				t.Errorf("splitSRVPortName(%q) = %q, want %q", c.portName, proto, c.wantProto)
+			}
+		})
+	}
+}
+
+func TestServiceLabel(t *testing.T) {
+	cases := []struct {
+		host string
+		want string
+	}{
+		{"ldap.example.com", "ldap"},
+		{"singlelabel", "singlelabel"},
+	}
+	for _, c := range cases {
+		if got := serviceLabel(c.host); got != c.want {
+			t.Errorf("serviceLabel(%q) = %q, want %q", c.host, got, c.want)
+		}
+	}
+}
+
+func TestClampRefreshDelay(t *testing.T) {
+	cases := []struct {
+		name string
+		ttl  time.Duration
+		want time.Duration
+	}{
+		{"below minimum", 100 * time.Millisecond, minRefreshDelay},
+		{"above maximum", time.Hour, maxRefreshDelay},
+		{"within range", 5 * time.Second, 5 * time.Second},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := clampRefreshDelay(c.ttl); got != c.want {
+				t.Errorf("clampRefreshDelay(%v) = %v, want %v", c.ttl, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analysis runs cross-resource semantic checks over a snapshot of
+// typed Istio configs, after per-resource validation (model.Validate*) has
+// already passed. Where per-resource validation can only ever see one
+// object at a time, an Analyzer here can check things like "does this
+// VirtualService's destination subset actually exist in some
+// DestinationRule" that no single object's validator could ever catch on
+// its own.
+package analysis
+
+import "istio.io/istio/pkg/config/analysis/diag"
+
+// Analyzer inspects a Snapshot and appends any diagnostics it finds to the
+// returned diag.Messages.
+type Analyzer interface {
+	// Name is a short, stable identifier for the analyzer. Analyze runs every
+	// default analyzer; it has no way to disable one by name.
+	Name() string
+	Analyze(snapshot *Snapshot) diag.Messages
+}
+
+// analyzerFunc adapts a plain function to the Analyzer interface.
+type analyzerFunc struct {
+	name string
+	fn   func(*Snapshot) diag.Messages
+}
+
+func (a analyzerFunc) Name() string                             { return a.name }
+func (a analyzerFunc) Analyze(snapshot *Snapshot) diag.Messages { return a.fn(snapshot) }
+
+// defaultAnalyzers is the fixed set of analyzers run by Analyze. None of
+// them is exported, so code outside this package can run only the whole
+// set, through Analyze.
+var defaultAnalyzers = []Analyzer{
+	analyzerFunc{name: "virtualservice-destination-exists", fn: analyzeVirtualServiceDestinations},
+	analyzerFunc{name: "gateway-port-on-workload", fn: analyzeGatewayPortOnWorkload},
+	analyzerFunc{name: "gateway-port-name-uniqueness", fn: analyzeGatewayPortNameUniqueness},
+	analyzerFunc{name: "authorizationpolicy-selector-matches", fn: analyzeAuthorizationPolicySelectors},
+	analyzerFunc{name: "destinationrule-mtls-consistency", fn: analyzeDestinationRuleMTLSConsistency},
+}
+
+// Analyze runs every registered analyzer over snapshot and returns the
+// combined diagnostics.
+func Analyze(snapshot *Snapshot) diag.Messages {
+	var all diag.Messages
+	for _, a := range defaultAnalyzers {
+		all = append(all, a.Analyze(snapshot)...)
+	}
+	return all
+}
@@ -0,0 +1,198 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+
+	networking "istio.io/api/networking/v1alpha3"
+
+	"istio.io/istio/pkg/config/analysis/diag"
+)
+
+// analyzeVirtualServiceDestinations checks that every VirtualService HTTP
+// route destination naming a subset actually has a matching subset defined
+// in some DestinationRule for that host.
+func analyzeVirtualServiceDestinations(s *Snapshot) diag.Messages {
+	var messages diag.Messages
+
+	subsetsByHost := make(map[string]map[string]bool)
+	for _, dr := range s.DestinationRules {
+		if dr.Spec == nil {
+			continue
+		}
+		subsets := subsetsByHost[dr.Spec.Host]
+		if subsets == nil {
+			subsets = make(map[string]bool)
+			subsetsByHost[dr.Spec.Host] = subsets
+		}
+		for _, subset := range dr.Spec.Subsets {
+			subsets[subset.Name] = true
+		}
+	}
+
+	for _, vs := range s.VirtualServices {
+		if vs.Spec == nil {
+			continue
+		}
+		for _, http := range vs.Spec.Http {
+			for _, route := range http.Route {
+				dest := route.Destination
+				if dest == nil || dest.Subset == "" {
+					continue
+				}
+				if !subsetsByHost[dest.Host][dest.Subset] {
+					messages.Add(ReferencedResourceNotFound, vs.Origin("VirtualService"),
+						vs.Name, "DestinationRule subset "+dest.Host+"/"+dest.Subset)
+				}
+			}
+		}
+	}
+
+	return messages
+}
+
+// analyzeGatewayPortOnWorkload checks that each Gateway's selector actually
+// matches at least one workload observed in the mesh - a Gateway whose
+// selector matches nothing will never have its ports exposed anywhere.
+func analyzeGatewayPortOnWorkload(s *Snapshot) diag.Messages {
+	var messages diag.Messages
+
+	for _, gw := range s.Gateways {
+		if gw.Spec == nil {
+			continue
+		}
+		if !s.selectorMatchesAnyWorkload(gw.Spec.Selector) {
+			messages.Add(GatewayPortNotOnWorkload, gw.Origin("Gateway"), gw.Name)
+		}
+	}
+
+	return messages
+}
+
+// analyzeGatewayPortNameUniqueness checks that, for every VirtualService
+// bound to more than one Gateway, no two of those Gateways declare a server
+// with the same Servers[i].Port.Name - Envoy merges the listeners of
+// Gateways bound together by a shared VirtualService, so a Port.Name
+// collision between them is ambiguous at the listener level even though each
+// Gateway is valid on its own.
+func analyzeGatewayPortNameUniqueness(s *Snapshot) diag.Messages {
+	var messages diag.Messages
+
+	portNamesByGateway := make(map[string][]string)
+	for _, gw := range s.Gateways {
+		if gw.Spec == nil {
+			continue
+		}
+		for _, server := range gw.Spec.Servers {
+			if server.Port == nil || server.Port.Name == "" {
+				continue
+			}
+			portNamesByGateway[gw.Name] = append(portNamesByGateway[gw.Name], server.Port.Name)
+		}
+	}
+
+	for _, vs := range s.VirtualServices {
+		if vs.Spec == nil || len(vs.Spec.Gateways) < 2 {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		reported := make(map[string]bool)
+		for _, gwRef := range vs.Spec.Gateways {
+			name := gatewayNameFromRef(gwRef)
+			if name == "mesh" {
+				continue
+			}
+			for _, portName := range portNamesByGateway[name] {
+				if seen[portName] && !reported[portName] {
+					messages.Add(GatewayDuplicatePortName, vs.Origin("VirtualService"), portName, vs.Name)
+					reported[portName] = true
+				}
+				seen[portName] = true
+			}
+		}
+	}
+
+	return messages
+}
+
+// gatewayNameFromRef strips an optional "namespace/" prefix from one of a
+// VirtualService's Gateways entries, the same shorthand istioctl accepts for
+// referencing a Gateway in another namespace.
+func gatewayNameFromRef(ref string) string {
+	if i := strings.LastIndexByte(ref, '/'); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// analyzeAuthorizationPolicySelectors checks that each AuthorizationPolicy's
+// selector matches at least one workload label set observed in the
+// snapshot; an AuthorizationPolicy that matches nothing is either dead
+// config or a typo in the selector.
+func analyzeAuthorizationPolicySelectors(s *Snapshot) diag.Messages {
+	var messages diag.Messages
+
+	for _, ap := range s.AuthorizationPolicies {
+		if ap.Spec == nil || ap.Spec.Selector == nil {
+			continue
+		}
+		if !s.selectorMatchesAnyWorkload(ap.Spec.Selector.MatchLabels) {
+			messages.Add(AuthorizationPolicyNoMatchingWorkloads, ap.Origin("AuthorizationPolicy"), ap.Name)
+		}
+	}
+
+	return messages
+}
+
+// analyzeDestinationRuleMTLSConsistency checks that a DestinationRule
+// requiring MUTUAL TLS to a host is paired with a Gateway server whose
+// TLSOptions.Mode is also MUTUAL; a client configured for mutual TLS against
+// a server that only terminates simple TLS will fail the handshake.
+func analyzeDestinationRuleMTLSConsistency(s *Snapshot) diag.Messages {
+	var messages diag.Messages
+
+	mutualServersByHost := make(map[string]bool)
+	for _, gw := range s.Gateways {
+		if gw.Spec == nil {
+			continue
+		}
+		for _, server := range gw.Spec.Servers {
+			requiresMutual := server.Tls != nil && server.Tls.Mode == networking.Server_TLSOptions_MUTUAL
+			for _, host := range server.Hosts {
+				if requiresMutual {
+					mutualServersByHost[host] = true
+				} else if _, exists := mutualServersByHost[host]; !exists {
+					mutualServersByHost[host] = false
+				}
+			}
+		}
+	}
+
+	for _, dr := range s.DestinationRules {
+		if dr.Spec == nil || dr.Spec.TrafficPolicy == nil || dr.Spec.TrafficPolicy.Tls == nil {
+			continue
+		}
+		if dr.Spec.TrafficPolicy.Tls.Mode != networking.TLSSettings_MUTUAL {
+			continue
+		}
+		if mutual, exists := mutualServersByHost[dr.Spec.Host]; exists && !mutual {
+			messages.Add(MTLSSettingsMismatch, dr.Origin("DestinationRule"), dr.Name, dr.Spec.Host)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+)
+
+func gatewayEntry(name string, portNames ...string) GatewayEntry {
+	servers := make([]*networking.Server, 0, len(portNames))
+	for _, pn := range portNames {
+		servers = append(servers, &networking.Server{Port: &networking.Port{Name: pn}})
+	}
+	return GatewayEntry{
+		Resource: Resource{Name: name},
+		Spec:     &networking.Gateway{Servers: servers},
+	}
+}
+
+func virtualServiceEntry(name string, gateways ...string) VirtualServiceEntry {
+	return VirtualServiceEntry{
+		Resource: Resource{Name: name},
+		Spec:     &networking.VirtualService{Gateways: gateways},
+	}
+}
+
+func TestAnalyzeGatewayPortNameUniqueness(t *testing.T) {
+	s := &Snapshot{
+		Gateways: []GatewayEntry{
+			gatewayEntry("gw-a", "http"),
+			gatewayEntry("gw-b", "http"),
+			gatewayEntry("gw-c", "https"),
+		},
+		VirtualServices: []VirtualServiceEntry{
+			virtualServiceEntry("vs-colliding", "gw-a", "gw-b"),
+			virtualServiceEntry("vs-distinct", "gw-a", "gw-c"),
+			virtualServiceEntry("vs-single-gateway", "gw-a"),
+		},
+	}
+
+	messages := analyzeGatewayPortNameUniqueness(s)
+	if len(messages) != 1 {
+		t.Fatalf("analyzeGatewayPortNameUniqueness() = %d messages, want 1: %v", len(messages), messages)
+	}
+	if messages[0].Type != GatewayDuplicatePortName {
+		t.Errorf("message type = %v, want GatewayDuplicatePortName", messages[0].Type)
+	}
+	if messages[0].Origin.Name != "vs-colliding" {
+		t.Errorf("message origin = %v, want vs-colliding", messages[0].Origin)
+	}
+}
+
+func TestAnalyzeGatewayPortNameUniquenessIgnoresMesh(t *testing.T) {
+	s := &Snapshot{
+		Gateways: []GatewayEntry{
+			gatewayEntry("gw-a", "http"),
+		},
+		VirtualServices: []VirtualServiceEntry{
+			virtualServiceEntry("vs-mesh", "mesh", "gw-a"),
+		},
+	}
+	if messages := analyzeGatewayPortNameUniqueness(s); len(messages) != 0 {
+		t.Errorf("analyzeGatewayPortNameUniqueness() = %v, want no messages", messages)
+	}
+}
+
+func TestAnalyzeGatewayPortNameUniquenessResolvesNamespacedRef(t *testing.T) {
+	s := &Snapshot{
+		Gateways: []GatewayEntry{
+			gatewayEntry("gw-a", "http"),
+			gatewayEntry("gw-b", "http"),
+		},
+		VirtualServices: []VirtualServiceEntry{
+			virtualServiceEntry("vs-namespaced", "istio-system/gw-a", "gw-b"),
+		},
+	}
+	if messages := analyzeGatewayPortNameUniqueness(s); len(messages) != 1 {
+		t.Errorf("analyzeGatewayPortNameUniqueness() = %v, want 1 message", messages)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import "istio.io/istio/pkg/config/analysis/diag"
+
+// Message codes produced by the analyzers in this package. Codes are
+// stable and documented; add new ones at the end of the range they belong
+// to rather than renumbering existing codes.
+var (
+	ReferencedResourceNotFound = &diag.MessageType{
+		Code:     "IST0101",
+		Name:     "ReferencedResourceNotFound",
+		Level:    diag.Error,
+		Template: "%s references %s which does not exist",
+	}
+
+	GatewayPortNotOnWorkload = &diag.MessageType{
+		Code:     "IST0110",
+		Name:     "GatewayPortNotOnWorkload",
+		Level:    diag.Warning,
+		Template: "the gateway refers to a port that is not exposed on any workload matching its selector (%s)",
+	}
+
+	GatewayDuplicatePortName = &diag.MessageType{
+		Code:  "IST0111",
+		Name:  "GatewayDuplicatePortName",
+		Level: diag.Error,
+		Template: "port name %q is used by more than one Gateway bound to VirtualService %s; " +
+			"Gateways sharing a VirtualService must use distinct Servers[].Port.Name values",
+	}
+
+	AuthorizationPolicyNoMatchingWorkloads = &diag.MessageType{
+		Code:     "IST0112",
+		Name:     "AuthorizationPolicyNoMatchingWorkloads",
+		Level:    diag.Warning,
+		Template: "no workload matches the AuthorizationPolicy selector (%s)",
+	}
+
+	MTLSSettingsMismatch = &diag.MessageType{
+		Code:     "IST0113",
+		Name:     "MTLSSettingsMismatch",
+		Level:    diag.Warning,
+		Template: "DestinationRule %s requires MUTUAL TLS to %s, but its Gateway server does not require MUTUAL TLS",
+	}
+)
@@ -0,0 +1,132 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diag defines the structured diagnostic messages produced by
+// config analyzers: a message code, a severity, the resource the message
+// is about, and a human-readable explanation.
+package diag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Level is the severity of a Message.
+type Level int
+
+const (
+	// Info indicates a message that is informational only, and does not
+	// necessarily imply a misconfiguration.
+	Info Level = iota
+	// Warning indicates a likely misconfiguration that won't necessarily
+	// break traffic.
+	Warning
+	// Error indicates a misconfiguration that will likely break traffic.
+	Error
+)
+
+func (l Level) String() string {
+	switch l {
+	case Info:
+		return "Info"
+	case Warning:
+		return "Warning"
+	case Error:
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+// MessageType is the fixed template for a class of diagnostic (e.g.
+// "IST0101 ReferencedResourceNotFound"). Analyzers construct a Message by
+// supplying an Origin and format parameters for a MessageType.
+type MessageType struct {
+	// Code is the stable, documented message code, e.g. "IST0101".
+	Code string
+	// Name is a short, human-readable name for the message type, e.g.
+	// "ReferencedResourceNotFound".
+	Name string
+	// Level is the default severity for messages of this type.
+	Level Level
+	// Template is a fmt.Sprintf template describing the problem.
+	Template string
+}
+
+// Origin identifies the config resource a Message is about.
+type Origin struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func (o Origin) String() string {
+	if o.Namespace == "" {
+		return fmt.Sprintf("%s/%s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s/%s.%s", o.Kind, o.Name, o.Namespace)
+}
+
+// Message is a single diagnostic produced by an analyzer.
+type Message struct {
+	Type   *MessageType
+	Origin Origin
+	Params []interface{}
+}
+
+// NewMessage constructs a Message from a MessageType, the resource it's
+// about, and the template's format parameters.
+func NewMessage(t *MessageType, origin Origin, params ...interface{}) Message {
+	return Message{Type: t, Origin: origin, Params: params}
+}
+
+// Level returns the message's severity.
+func (m Message) Level() Level {
+	return m.Type.Level
+}
+
+func (m Message) String() string {
+	return fmt.Sprintf("%s (%s) %s: %s", m.Type.Code, m.Level(), m.Origin, fmt.Sprintf(m.Type.Template, m.Params...))
+}
+
+// Messages is a collection of diagnostics produced by one or more
+// analyzers.
+type Messages []Message
+
+// Add appends a message constructed from a MessageType, origin, and
+// template parameters.
+func (ms *Messages) Add(t *MessageType, origin Origin, params ...interface{}) {
+	*ms = append(*ms, NewMessage(t, origin, params...))
+}
+
+// SortedByLevel returns a copy of ms ordered by descending severity (Error
+// before Warning before Info), preserving relative order within a level.
+func (ms Messages) SortedByLevel() Messages {
+	out := make(Messages, len(ms))
+	copy(out, ms)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Level() > out[j].Level()
+	})
+	return out
+}
+
+// HasErrors reports whether ms contains at least one Error-level message.
+func (ms Messages) HasErrors() bool {
+	for _, m := range ms {
+		if m.Level() == Error {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	networking "istio.io/api/networking/v1alpha3"
+	securitybeta "istio.io/api/security/v1beta1"
+
+	"istio.io/istio/pkg/config/analysis/diag"
+)
+
+// Resource pairs a typed config object with the metadata ConfigAnalyzer
+// needs to report a diag.Message about it.
+type Resource struct {
+	Name      string
+	Namespace string
+}
+
+// Origin builds the diag.Origin analyzers attach to messages about r.
+func (r Resource) Origin(kind string) diag.Origin {
+	return diag.Origin{Kind: kind, Name: r.Name, Namespace: r.Namespace}
+}
+
+// VirtualServiceEntry pairs a VirtualService with its resource metadata.
+type VirtualServiceEntry struct {
+	Resource
+	Spec *networking.VirtualService
+}
+
+// DestinationRuleEntry pairs a DestinationRule with its resource metadata.
+type DestinationRuleEntry struct {
+	Resource
+	Spec *networking.DestinationRule
+}
+
+// GatewayEntry pairs a Gateway with its resource metadata.
+type GatewayEntry struct {
+	Resource
+	Spec *networking.Gateway
+}
+
+// ServiceEntryEntry pairs a ServiceEntry with its resource metadata.
+type ServiceEntryEntry struct {
+	Resource
+	Spec *networking.ServiceEntry
+}
+
+// SidecarEntry pairs a Sidecar with its resource metadata.
+type SidecarEntry struct {
+	Resource
+	Spec *networking.Sidecar
+}
+
+// AuthorizationPolicyEntry pairs an AuthorizationPolicy with its resource
+// metadata.
+type AuthorizationPolicyEntry struct {
+	Resource
+	Spec *securitybeta.AuthorizationPolicy
+}
+
+// Snapshot is the full set of typed configs an analysis pass runs over, plus
+// the workload label sets observed in the mesh (used to check that
+// selectors in Gateway/AuthorizationPolicy actually match
+// something).
+type Snapshot struct {
+	VirtualServices       []VirtualServiceEntry
+	DestinationRules      []DestinationRuleEntry
+	Gateways              []GatewayEntry
+	ServiceEntries        []ServiceEntryEntry
+	Sidecars              []SidecarEntry
+	AuthorizationPolicies []AuthorizationPolicyEntry
+
+	// WorkloadLabels is the set of label sets observed across workloads in
+	// the mesh, used to check that a selector matches at least one
+	// workload.
+	WorkloadLabels []map[string]string
+}
+
+// selectorMatchesAnyWorkload reports whether selector (a match-labels map)
+// is satisfied by at least one label set in s.WorkloadLabels. A nil/empty
+// selector matches everything.
+func (s *Snapshot) selectorMatchesAnyWorkload(selector map[string]string) bool {
+	if len(selector) == 0 {
+		return true
+	}
+	for _, labels := range s.WorkloadLabels {
+		if labelsContain(labels, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+func labelsContain(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	networking "istio.io/api/networking/v1alpha3"
+	routing "istio.io/api/routing/v1alpha1"
+)
+
+// exportedKinds lists, in output order, the CRD kinds Export knows how to
+// emit a schema for, paired with a zero-value instance of their spec proto
+// used purely for descriptor reflection.
+var exportedKinds = []struct {
+	kind string
+	spec proto.Message
+}{
+	{"VirtualService", &networking.VirtualService{}},
+	{"Gateway", &networking.Gateway{}},
+	{"DestinationRule", &networking.DestinationRule{}},
+	{"EgressRule", &routing.EgressRule{}},
+	{"MeshConfig", &meshconfig.MeshConfig{}},
+	{"ProxyConfig", &meshconfig.ProxyConfig{}},
+}
+
+// Export writes the schema of every kind in exportedKinds to w, in the
+// given format:
+//
+//   - "json-schema" emits a map of kind name to a standalone JSON-Schema
+//     document for that kind's spec.
+//   - "openapi" emits a map of kind name to a CRD-embeddable
+//     openAPIV3Schema object (spec nested under "properties.spec"), ready
+//     to paste into a CustomResourceDefinition.
+//
+// Any other format is a non-nil error.
+func Export(w io.Writer, format string) error {
+	out := make(map[string]interface{}, len(exportedKinds))
+
+	for _, k := range exportedKinds {
+		specSchema := schemaForMessage(k.spec)
+
+		switch format {
+		case "json-schema":
+			doc := map[string]interface{}{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"title":   k.kind,
+			}
+			for key, val := range specSchema {
+				doc[key] = val
+			}
+			out[k.kind] = doc
+		case "openapi":
+			out[k.kind] = map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"apiVersion": map[string]interface{}{"type": "string"},
+					"kind":       map[string]interface{}{"type": "string"},
+					"metadata":   map[string]interface{}{"type": "object"},
+					"spec":       specSchema,
+				},
+			}
+		default:
+			return fmt.Errorf("validation: unknown export format %q, want \"json-schema\" or \"openapi\"", format)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"istio.io/istio/pilot/pkg/model"
+)
+
+// This file re-exports a handful of individual field-validation primitives
+// from pilot/pkg/model, the same way Consul's internal/catalog/exports.go
+// re-exports catalog internals for its other packages. pilot/pkg/model is
+// the engine that backs istioctl and the validating webhook; downstream
+// operators writing their own admission webhooks or CI lint checks against
+// Istio CRDs shouldn't have to vendor it (or track its internal refactors)
+// just to reuse a single port-name or port-number check. Everything below
+// is a thin, stable wrapper - see the referenced model function for the
+// full doc comment.
+
+// NetworkEndpoint is the re-exported form of model.NetworkEndpoint, the
+// type ValidateNetworkEndpointAddress validates.
+type NetworkEndpoint = model.NetworkEndpoint
+
+// ProtocolValidator is the re-exported form of model.ProtocolValidator, the
+// callback type RegisterProtocol accepts.
+type ProtocolValidator = model.ProtocolValidator
+
+// ValidatePortName reports whether name is a valid ServiceEntry port name:
+// a DNS-1123 label, or a "low-high" port-range expression.
+func ValidatePortName(name string) error {
+	return model.ValidatePortName(name)
+}
+
+// ValidateProtocol reports whether protocol is a recognized Istio port
+// protocol, including any name registered via RegisterProtocol.
+func ValidateProtocol(protocol string) error {
+	return model.ValidateProtocol(protocol)
+}
+
+// ValidatePort reports whether port is a valid TCP/UDP port number.
+func ValidatePort(port int) error {
+	return model.ValidatePort(port)
+}
+
+// AppendErrors combines err with errs the same way Istio's own Validate*
+// functions accumulate errors: nil inputs are dropped, and the result is
+// nil iff every input was nil.
+func AppendErrors(err error, errs ...error) error {
+	return model.AppendErrors(err, errs...)
+}
+
+// ValidateNetworkEndpointAddress reports whether n's address is valid for
+// n's Family: a plain IPv4/IPv6 address (rejecting an IPv4-mapped-IPv6
+// literal unless model.SetAllowIPv4MappedIPv6 has enabled it) for AddressFamilyTCP, or
+// a unix domain socket path for AddressFamilyUnix. It does not cover CIDR-
+// block endpoints - those are validated inline by ValidateServiceEntry, not
+// through a NetworkEndpoint.
+func ValidateNetworkEndpointAddress(n *NetworkEndpoint) error {
+	return model.ValidateNetworkEndpointAddress(n)
+}
+
+// RegisterProtocol teaches ValidateProtocol a protocol name it doesn't
+// recognize out of the box (e.g. "MEMCACHED", "KAFKA"), without forking
+// Istio's Protocol enum. See model.RegisterProtocol.
+func RegisterProtocol(name string, v ProtocolValidator) {
+	model.RegisterProtocol(name, v)
+}
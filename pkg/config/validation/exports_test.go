@@ -0,0 +1,102 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validation_test locks in the public shape of this package's
+// re-exports: downstream admission-webhook authors depend on these
+// signatures and behaviors directly, so a change here is a breaking change
+// for them even if it's invisible to everything else in this repo.
+package validation_test
+
+import (
+	"testing"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/validation"
+)
+
+func TestValidatePortName(t *testing.T) {
+	if err := validation.ValidatePortName("http"); err != nil {
+		t.Errorf("ValidatePortName(http) = %v, want nil", err)
+	}
+	if err := validation.ValidatePortName("Not_Valid"); err == nil {
+		t.Error("ValidatePortName(Not_Valid) = nil, want error")
+	}
+}
+
+func TestValidateProtocol(t *testing.T) {
+	if err := validation.ValidateProtocol("HTTP"); err != nil {
+		t.Errorf("ValidateProtocol(HTTP) = %v, want nil", err)
+	}
+	if err := validation.ValidateProtocol("NOT-A-PROTOCOL"); err == nil {
+		t.Error("ValidateProtocol(NOT-A-PROTOCOL) = nil, want error")
+	}
+}
+
+func TestValidatePort(t *testing.T) {
+	if err := validation.ValidatePort(8080); err != nil {
+		t.Errorf("ValidatePort(8080) = %v, want nil", err)
+	}
+	if err := validation.ValidatePort(-1); err == nil {
+		t.Error("ValidatePort(-1) = nil, want error")
+	}
+}
+
+func TestAppendErrors(t *testing.T) {
+	if err := validation.AppendErrors(nil); err != nil {
+		t.Errorf("AppendErrors(nil) = %v, want nil", err)
+	}
+	if err := validation.AppendErrors(nil, nil, nil); err != nil {
+		t.Errorf("AppendErrors(nil, nil, nil) = %v, want nil", err)
+	}
+	if err := validation.AppendErrors(errBoom); err == nil {
+		t.Error("AppendErrors(errBoom) = nil, want error")
+	}
+}
+
+var errBoom = errFixture("boom")
+
+type errFixture string
+
+func (e errFixture) Error() string { return string(e) }
+
+func TestValidateNetworkEndpointAddress(t *testing.T) {
+	tcp := &validation.NetworkEndpoint{Address: "10.0.0.1", Family: model.AddressFamilyTCP}
+	if err := validation.ValidateNetworkEndpointAddress(tcp); err != nil {
+		t.Errorf("ValidateNetworkEndpointAddress(tcp) = %v, want nil", err)
+	}
+
+	badTCP := &validation.NetworkEndpoint{Address: "not-an-ip", Family: model.AddressFamilyTCP}
+	if err := validation.ValidateNetworkEndpointAddress(badTCP); err == nil {
+		t.Error("ValidateNetworkEndpointAddress(badTCP) = nil, want error")
+	}
+
+	unix := &validation.NetworkEndpoint{Address: "/var/run/istio.sock", Family: model.AddressFamilyUnix}
+	if err := validation.ValidateNetworkEndpointAddress(unix); err != nil {
+		t.Errorf("ValidateNetworkEndpointAddress(unix) = %v, want nil", err)
+	}
+}
+
+func TestRegisterProtocol(t *testing.T) {
+	called := false
+	validation.RegisterProtocol("EXPORTS-TEST-PROTO", func(string) error {
+		called = true
+		return nil
+	})
+	if err := validation.ValidateProtocol("EXPORTS-TEST-PROTO"); err != nil {
+		t.Errorf("ValidateProtocol(EXPORTS-TEST-PROTO) = %v, want nil", err)
+	}
+	if !called {
+		t.Error("RegisterProtocol's validator was not invoked by ValidateProtocol")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validation generates OpenAPI v3 / JSON-Schema documents from the
+// proto descriptors of Istio's config CRDs, so client-side tooling
+// (kubectl, IDE plugins, admission webhooks) can structurally validate a
+// resource without calling into model.DefaultValidators.
+package validation
+
+import (
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxSchemaDepth bounds recursion when a message type is nested within
+// itself (directly or through a cycle of message types); past this depth we
+// emit a bare object schema instead of expanding further.
+const maxSchemaDepth = 8
+
+// schemaForMessage walks msg's proto descriptor and returns its JSON-Schema
+// representation as a plain map, suitable for json.Marshal or embedding
+// under a CRD's openAPIV3Schema.
+func schemaForMessage(msg proto.Message) map[string]interface{} {
+	return schemaForDescriptor(proto.MessageReflect(msg).Descriptor(), 0)
+}
+
+func schemaForDescriptor(md protoreflect.MessageDescriptor, depth int) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object"}
+	if depth >= maxSchemaDepth {
+		return schema
+	}
+
+	fields := md.Fields()
+	properties := make(map[string]interface{}, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		properties[jsonName(fields.Get(i))] = schemaForField(fields.Get(i), depth)
+	}
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+	return schema
+}
+
+// jsonName returns the field's protoJSON name (lowerCamelCase), matching
+// what a real Kubernetes object's YAML/JSON would use.
+func jsonName(fd protoreflect.FieldDescriptor) string {
+	return string(fd.JSONName())
+}
+
+func schemaForField(fd protoreflect.FieldDescriptor, depth int) map[string]interface{} {
+	var item map[string]interface{}
+	switch {
+	case fd.IsMap():
+		item = map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaForKind(fd.MapValue(), depth),
+		}
+		return item
+	case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+		item = schemaForDescriptor(fd.Message(), depth+1)
+	default:
+		item = schemaForKind(fd, depth)
+	}
+
+	if fd.IsList() {
+		return map[string]interface{}{"type": "array", "items": item}
+	}
+	return item
+}
+
+func schemaForKind(fd protoreflect.FieldDescriptor, depth int) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number"}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return schemaForDescriptor(fd.Message(), depth+1)
+	default:
+		// Every remaining numeric kind (Int32/Int64/Uint32/Uint64/Sint32/
+		// Sint64/Fixed32/Fixed64/Sfixed32/Sfixed64) maps to a JSON integer.
+		return map[string]interface{}{"type": "integer"}
+	}
+}